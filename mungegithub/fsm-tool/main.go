@@ -0,0 +1,43 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+
+	utilflag "k8s.io/kubernetes/pkg/util/flag"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:   filepath.Base(os.Args[0]),
+		Short: "Tools to develop and debug mungegithub state machines",
+	}
+	root.SetGlobalNormalizationFunc(utilflag.WordSepNormalizeFunc)
+	root.AddCommand(simulateCommand())
+	root.AddCommand(replayCommand())
+	root.AddCommand(reportCommand())
+	root.AddCommand(validateCommand())
+
+	if err := root.Execute(); err != nil {
+		glog.Fatalf("%v\n", err)
+	}
+}
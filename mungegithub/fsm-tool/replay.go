@@ -0,0 +1,79 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	"k8s.io/contrib/mungegithub/mungers/fsm"
+
+	"github.com/spf13/cobra"
+)
+
+func replayCommand() *cobra.Command {
+	var machineFlags machineFlags
+	config := &github_util.Config{}
+	cmd := &cobra.Command{
+		Use:   "replay NUMBER",
+		Short: "Fetch the history of an issue from github and trace it through the state machine",
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("replay expects exactly one issue number")
+			}
+			number, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid issue number %q: %v", args[0], err)
+			}
+			machine, err := machineFlags.load()
+			if err != nil {
+				return err
+			}
+			if err := config.PreExecute(); err != nil {
+				return err
+			}
+
+			obj, err := config.GetObject(number)
+			if err != nil {
+				return err
+			}
+			events, err := obj.GetEvents()
+			if err != nil {
+				return err
+			}
+			comments, err := obj.ListComments()
+			if err != nil {
+				return err
+			}
+			reviews, err := obj.ListReviews()
+			if err != nil {
+				return err
+			}
+
+			result, err := machine.ResumeTimeline(nil, obj.Issue, fsm.TimelineWithReviews(events, comments, reviews))
+			if err != nil {
+				return err
+			}
+			return result.WriteTrace(os.Stdout)
+		},
+	}
+	machineFlags.addFlags(cmd)
+	config.AddRootFlags(cmd)
+	return cmd
+}
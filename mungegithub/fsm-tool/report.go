@@ -0,0 +1,86 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	"k8s.io/contrib/mungegithub/mungers/fsm"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+)
+
+func reportCommand() *cobra.Command {
+	var machineFlags machineFlags
+	var format string
+	var labelPrefixes []string
+	config := &github_util.Config{}
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Count how many open issues and PRs are in each state",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("--format must be table or json, not %q", format)
+			}
+			machine, err := machineFlags.load()
+			if err != nil {
+				return err
+			}
+			if err := config.PreExecute(); err != nil {
+				return err
+			}
+
+			summary := fsm.NewSummary(machine, labelPrefixes)
+			err = config.ForEachIssueDo(func(obj *github_util.MungeObject) error {
+				events, err := obj.GetEvents()
+				if err != nil {
+					return err
+				}
+				comments, err := obj.ListComments()
+				if err != nil {
+					return err
+				}
+				reviews, err := obj.ListReviews()
+				if err != nil {
+					return err
+				}
+				result, err := machine.ResumeTimeline(nil, obj.Issue, fsm.TimelineWithReviews(events, comments, reviews))
+				if err != nil {
+					glog.Errorf("Failed to process #%d: %v", obj.Number(), err)
+					return err
+				}
+				summary.Add(obj.Issue, result.State)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				return summary.WriteJSON(os.Stdout)
+			}
+			return summary.WriteTable(os.Stdout)
+		},
+	}
+	machineFlags.addFlags(cmd)
+	config.AddRootFlags(cmd)
+	cmd.Flags().StringVar(&format, "format", "table", "Output format, table or json")
+	cmd.Flags().StringSliceVar(&labelPrefixes, "label-prefixes", []string{}, "Break down the counts by the issue labels starting with one of these prefixes, e.g. sig/")
+	return cmd
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	"k8s.io/contrib/mungegithub/mungers/fsm"
+
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+// readJSON decodes the file into `into`. An empty path is ignored.
+func readJSON(path string, into interface{}) error {
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	return nil
+}
+
+// machineFlags selects the state machine to use.
+type machineFlags struct {
+	config string
+	preset string
+}
+
+func (m *machineFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&m.config, "config", "", "YAML file describing the state machine")
+	cmd.Flags().StringVar(&m.preset, "preset", "", fmt.Sprintf("Name of a built-in state machine, one of %v", fsm.Presets()))
+}
+
+func (m *machineFlags) load() (*fsm.Machine, error) {
+	if (m.config == "") == (m.preset == "") {
+		return nil, fmt.Errorf("exactly one of --config or --preset is required")
+	}
+	if m.preset != "" {
+		return fsm.LoadPreset(m.preset)
+	}
+	return fsm.LoadMachine(m.config)
+}
+
+func simulateCommand() *cobra.Command {
+	var machineFlags machineFlags
+	var issuePath, eventsPath, commentsPath, reviewsPath string
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay stored events and comments of an issue through the state machine, without touching github",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			machine, err := machineFlags.load()
+			if err != nil {
+				return err
+			}
+
+			issue := &github.Issue{}
+			events := []*github.IssueEvent{}
+			comments := []*github.IssueComment{}
+			reviews := []*github_util.PullRequestReview{}
+			if err := readJSON(issuePath, issue); err != nil {
+				return err
+			}
+			if err := readJSON(eventsPath, &events); err != nil {
+				return err
+			}
+			if err := readJSON(commentsPath, &comments); err != nil {
+				return err
+			}
+			if err := readJSON(reviewsPath, &reviews); err != nil {
+				return err
+			}
+
+			result, err := machine.ResumeTimeline(nil, issue, fsm.TimelineWithReviews(events, comments, reviews))
+			if err != nil {
+				return err
+			}
+			return result.WriteTrace(os.Stdout)
+		},
+	}
+	machineFlags.addFlags(cmd)
+	cmd.Flags().StringVar(&issuePath, "issue", "", "JSON file containing the issue, as returned by the github API")
+	cmd.Flags().StringVar(&eventsPath, "events", "", "JSON file containing the list of events of the issue")
+	cmd.Flags().StringVar(&commentsPath, "comments", "", "JSON file containing the list of comments of the issue")
+	cmd.Flags().StringVar(&reviewsPath, "reviews", "", "JSON file containing the list of reviews of the PR")
+	return cmd
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func validateCommand() *cobra.Command {
+	var machineFlags machineFlags
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check a state machine for errors and likely mistakes",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			machine, err := machineFlags.load()
+			if err != nil {
+				return err
+			}
+			warnings := machine.Validate()
+			for _, warning := range warnings {
+				fmt.Printf("warning: %s\n", warning)
+			}
+			if len(warnings) != 0 {
+				return fmt.Errorf("found %d problems", len(warnings))
+			}
+			fmt.Println("OK")
+			return nil
+		},
+	}
+	machineFlags.addFlags(cmd)
+	return cmd
+}
@@ -171,6 +171,8 @@ type Config struct {
 	// When we clear analytics we store the last values here
 	lastAnalytics analytics
 	analytics     analytics
+	// analyticsLock protects analytics when issues are munged in parallel
+	analyticsLock sync.Mutex
 }
 
 type analytic struct {
@@ -179,6 +181,8 @@ type analytic struct {
 }
 
 func (a *analytic) Call(config *Config, response *github.Response) {
+	config.analyticsLock.Lock()
+	defer config.analyticsLock.Unlock()
 	if response != nil && response.Response.Header.Get(httpcache.XFromCache) != "" {
 		config.analytics.cachedAPICount++
 		a.CachedCount++
@@ -196,11 +200,14 @@ type analytics struct {
 
 	AddLabels            analytic
 	AddLabelToRepository analytic
+	EditRepoLabel        analytic
+	DeleteRepoLabel      analytic
 	RemoveLabels         analytic
 	ListCollaborators    analytic
 	GetIssue             analytic
 	CloseIssue           analytic
 	CreateIssue          analytic
+	CreatePR             analytic
 	ListIssues           analytic
 	ListIssueEvents      analytic
 	ListCommits          analytic
@@ -211,12 +218,16 @@ type analytics struct {
 	SetStatus            analytic
 	GetPR                analytic
 	AssignPR             analytic
+	UnassignPR           analytic
+	AddAssignees         analytic
 	ClosePR              analytic
 	OpenPR               analytic
 	GetContents          analytic
 	ListComments         analytic
 	ListReviewComments   analytic
+	ListReviews          analytic
 	CreateComment        analytic
+	EditComment          analytic
 	DeleteComment        analytic
 	Merge                analytic
 	GetUser              analytic
@@ -232,11 +243,14 @@ func (a analytics) print() {
 	w.Init(buf, 0, 0, 1, ' ', tabwriter.AlignRight)
 	fmt.Fprintf(w, "AddLabels\t%d\t\n", a.AddLabels.Count)
 	fmt.Fprintf(w, "AddLabelToRepository\t%d\t\n", a.AddLabelToRepository.Count)
+	fmt.Fprintf(w, "EditRepoLabel\t%d\t\n", a.EditRepoLabel.Count)
+	fmt.Fprintf(w, "DeleteRepoLabel\t%d\t\n", a.DeleteRepoLabel.Count)
 	fmt.Fprintf(w, "RemoveLabels\t%d\t\n", a.RemoveLabels.Count)
 	fmt.Fprintf(w, "ListCollaborators\t%d\t\n", a.ListCollaborators.Count)
 	fmt.Fprintf(w, "GetIssue\t%d\t\n", a.GetIssue.Count)
 	fmt.Fprintf(w, "CloseIssue\t%d\t\n", a.CloseIssue.Count)
 	fmt.Fprintf(w, "CreateIssue\t%d\t\n", a.CreateIssue.Count)
+	fmt.Fprintf(w, "CreatePR\t%d\t\n", a.CreatePR.Count)
 	fmt.Fprintf(w, "ListIssues\t%d\t\n", a.ListIssues.Count)
 	fmt.Fprintf(w, "ListIssueEvents\t%d\t\n", a.ListIssueEvents.Count)
 	fmt.Fprintf(w, "ListCommits\t%d\t\n", a.ListCommits.Count)
@@ -247,12 +261,16 @@ func (a analytics) print() {
 	fmt.Fprintf(w, "SetStatus\t%d\t\n", a.SetStatus.Count)
 	fmt.Fprintf(w, "GetPR\t%d\t\n", a.GetPR.Count)
 	fmt.Fprintf(w, "AssignPR\t%d\t\n", a.AssignPR.Count)
+	fmt.Fprintf(w, "UnassignPR\t%d\t\n", a.UnassignPR.Count)
+	fmt.Fprintf(w, "AddAssignees\t%d\t\n", a.AddAssignees.Count)
 	fmt.Fprintf(w, "ClosePR\t%d\t\n", a.ClosePR.Count)
 	fmt.Fprintf(w, "OpenPR\t%d\t\n", a.OpenPR.Count)
 	fmt.Fprintf(w, "GetContents\t%d\t\n", a.GetContents.Count)
 	fmt.Fprintf(w, "ListReviewComments\t%d\t\n", a.ListReviewComments.Count)
+	fmt.Fprintf(w, "ListReviews\t%d\t\n", a.ListReviews.Count)
 	fmt.Fprintf(w, "ListComments\t%d\t\n", a.ListComments.Count)
 	fmt.Fprintf(w, "CreateComment\t%d\t\n", a.CreateComment.Count)
+	fmt.Fprintf(w, "EditComment\t%d\t\n", a.EditComment.Count)
 	fmt.Fprintf(w, "DeleteComment\t%d\t\n", a.DeleteComment.Count)
 	fmt.Fprintf(w, "Merge\t%d\t\n", a.Merge.Count)
 	fmt.Fprintf(w, "GetUser\t%d\t\n", a.GetUser.Count)
@@ -272,6 +290,7 @@ type MungeObject struct {
 	events      []*github.IssueEvent
 	comments    []*github.IssueComment
 	prComments  []*github.PullRequestComment
+	reviews     []*PullRequestReview
 	commitFiles []*github.CommitFile
 	Annotations map[string]string //annotations are things you can set yourself.
 }
@@ -447,6 +466,8 @@ func (config *Config) NextExpectedUpdate(t time.Time) {
 // ResetAPICount will both reset the counters of how many api calls have been
 // made but will also print the information from the last run.
 func (config *Config) ResetAPICount() {
+	config.analyticsLock.Lock()
+	defer config.analyticsLock.Unlock()
 	since := time.Since(config.analytics.lastAPIReset)
 	config.analytics.apiPerSec = float64(config.analytics.apiCount) / since.Seconds()
 	config.lastAnalytics = config.analytics
@@ -558,6 +579,27 @@ func (config *Config) NewIssue(title, body string, labels []string, owner string
 	return obj, nil
 }
 
+// NewPR will open a pull request merging the `head` branch into `base`
+// and return an object for it.
+func (config *Config) NewPR(title, body, head, base string) (*MungeObject, error) {
+	config.analytics.CreatePR.Call(config, nil)
+	glog.Infof("Creating a PR: %q (%s -> %s)", title, head, base)
+	if config.DryRun {
+		return nil, fmt.Errorf("can't make PRs in dry-run mode")
+	}
+	pr, _, err := config.client.PullRequests.Create(config.Org, config.Project, &github.NewPullRequest{
+		Title: &title,
+		Body:  &body,
+		Head:  &head,
+		Base:  &base,
+	})
+	if err != nil {
+		glog.Errorf("createPR: %v", err)
+		return nil, err
+	}
+	return config.GetObject(*pr.Number)
+}
+
 // Branch returns the branch the PR is for. Return "" if this is not a PR or
 // it does not have the required information.
 func (obj *MungeObject) Branch() string {
@@ -1339,6 +1381,39 @@ func (obj *MungeObject) AssignPR(owner string) error {
 	return nil
 }
 
+// AddAssignees will add the `owners` to the assignees of the PR (or issue),
+// keeping the current ones, unlike AssignPR
+func (obj *MungeObject) AddAssignees(owners []string) error {
+	config := obj.config
+	prNum := *obj.Issue.Number
+	config.analytics.AddAssignees.Call(config, nil)
+	glog.Infof("Adding assignees %v to PR# %d", owners, prNum)
+	if config.DryRun {
+		return nil
+	}
+	if _, _, err := config.client.Issues.AddAssignees(config.Org, config.Project, prNum, owners); err != nil {
+		glog.Errorf("Error adding assignees %v to issue# %d: %v", owners, prNum, err)
+		return err
+	}
+	return nil
+}
+
+// UnassignPR will remove the `owner` from the assignees of the PR (or issue)
+func (obj *MungeObject) UnassignPR(owner string) error {
+	config := obj.config
+	prNum := *obj.Issue.Number
+	config.analytics.UnassignPR.Call(config, nil)
+	glog.Infof("Unassigning %v from PR# %d", owner, prNum)
+	if config.DryRun {
+		return nil
+	}
+	if _, _, err := config.client.Issues.RemoveAssignees(config.Org, config.Project, prNum, []string{owner}); err != nil {
+		glog.Errorf("Error unassigning %v from issue# %d: %v", owner, prNum, err)
+		return err
+	}
+	return nil
+}
+
 // CloseIssuef will close the given issue with a message
 func (obj *MungeObject) CloseIssuef(format string, args ...interface{}) error {
 	config := obj.config
@@ -1717,6 +1792,28 @@ func (obj *MungeObject) DeleteComment(comment *github.IssueComment) error {
 	return nil
 }
 
+// EditComment will replace the body of the given comment
+func (obj *MungeObject) EditComment(comment *github.IssueComment, body string) error {
+	config := obj.config
+	prNum := *obj.Issue.Number
+	config.analytics.EditComment.Call(config, nil)
+	if comment.ID == nil {
+		err := fmt.Errorf("Found a comment with nil id for Issue %d", prNum)
+		glog.Errorf("Found a comment with nil id for Issue %d", prNum)
+		return err
+	}
+	glog.Infof("Editing comment %d from Issue %d. Body:%q", *comment.ID, prNum, body)
+	if config.DryRun {
+		return nil
+	}
+	if _, _, err := config.client.Issues.EditComment(config.Org, config.Project, *comment.ID, &github.IssueComment{Body: &body}); err != nil {
+		glog.Errorf("Error editing comment: %v", err)
+		return err
+	}
+	comment.Body = &body
+	return nil
+}
+
 // IsMergeable will return if the PR is mergeable. It will pause and get the
 // PR again if github did not respond the first time. So the hopefully github
 // will have a response the second time. If we have no answer twice, we return
@@ -1843,6 +1940,32 @@ func (config *Config) ForEachIssueDo(fn MungeFunction) error {
 	return nil
 }
 
+// ForEachIssueDoParallel is like ForEachIssueDo, but calls fn on up to
+// `workers` issues at the same time. fn must be safe to call concurrently.
+func (config *Config) ForEachIssueDoParallel(workers int, fn MungeFunction) error {
+	if workers <= 1 {
+		return config.ForEachIssueDo(fn)
+	}
+	objs := make(chan *MungeObject)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range objs {
+				fn(obj)
+			}
+		}()
+	}
+	err := config.ForEachIssueDo(func(obj *MungeObject) error {
+		objs <- obj
+		return nil
+	})
+	close(objs)
+	wg.Wait()
+	return err
+}
+
 // ListAllIssues grabs all issues matching the options, so you don't have to
 // worry about paging. Enforces some constraints, like min/max PR number and
 // having a valid user.
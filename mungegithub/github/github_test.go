@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -239,6 +240,44 @@ func TestForEachIssueDo(t *testing.T) {
 	}
 }
 
+func TestForEachIssueDoParallel(t *testing.T) {
+	issues := []github.Issue{}
+	for i := 1; i <= 10; i++ {
+		issues = append(issues, *github_test.Issue("bob", i, nil, true))
+	}
+	client, server, mux := github_test.InitServer(t, nil, nil, nil, nil, nil, nil, nil)
+	defer server.Close()
+	config := &Config{
+		client:      client,
+		Org:         "foo",
+		Project:     "bar",
+		MaxPRNumber: 100,
+	}
+	mux.HandleFunc("/repos/foo/bar/issues", func(w http.ResponseWriter, r *http.Request) {
+		data, err := json.Marshal(issues)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	})
+
+	lock := sync.Mutex{}
+	seen := map[int]bool{}
+	err := config.ForEachIssueDoParallel(3, func(obj *MungeObject) error {
+		lock.Lock()
+		defer lock.Unlock()
+		seen[obj.Number()] = true
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(seen) != len(issues) {
+		t.Errorf("Expected %d issues, got %v", len(issues), seen)
+	}
+}
+
 func TestComputeStatus(t *testing.T) {
 	contextS := []string{"context"}
 	otherS := []string{"other context"}
@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+)
+
+const (
+	// TODO: remove when label descriptions are out of preview, and use
+	// go-github once it supports them.
+	mediaTypeLabelsPreview = "application/vnd.github.symmetra-preview+json"
+)
+
+// RepoLabel is a label of a repository, with its description. The vendored
+// go-github doesn't know about descriptions yet.
+type RepoLabel struct {
+	Name        *string `json:"name,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+func (config *Config) labelsRequest(method, u string, body interface{}, v interface{}) (*github.Response, error) {
+	req, err := config.client.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeLabelsPreview)
+	return config.client.Do(req, v)
+}
+
+// labelURL returns the API path of a label. Names such as "sig/node" must
+// be escaped.
+func labelURL(org, repo, name string) string {
+	return fmt.Sprintf("repos/%v/%v/labels/%v", org, repo, strings.Replace(url.QueryEscape(name), "+", "%20", -1))
+}
+
+// ListRepoLabels returns all the labels of the org/repo repository
+func (config *Config) ListRepoLabels(org, repo string) ([]*RepoLabel, error) {
+	allLabels := []*RepoLabel{}
+	page := 1
+	for {
+		glog.V(4).Infof("Fetching page %d of labels for %s/%s", page, org, repo)
+		u := fmt.Sprintf("repos/%v/%v/labels?per_page=100&page=%d", org, repo, page)
+		labels := []*RepoLabel{}
+		response, err := config.labelsRequest("GET", u, nil, &labels)
+		config.analytics.ListLabels.Call(config, response)
+		if err != nil {
+			return nil, err
+		}
+		allLabels = append(allLabels, labels...)
+		if response.LastPage == 0 || response.LastPage <= page {
+			break
+		}
+		page++
+	}
+	return allLabels, nil
+}
+
+// CreateRepoLabel creates the label in the org/repo repository
+func (config *Config) CreateRepoLabel(org, repo string, label *RepoLabel) error {
+	config.analytics.AddLabelToRepository.Call(config, nil)
+	glog.Infof("Adding label %v to %v/%v", *label.Name, org, repo)
+	if config.DryRun {
+		return nil
+	}
+	_, err := config.labelsRequest("POST", fmt.Sprintf("repos/%v/%v/labels", org, repo), label, nil)
+	return err
+}
+
+// EditRepoLabel updates the label `name` of the org/repo repository. It
+// can be renamed by changing label.Name.
+func (config *Config) EditRepoLabel(org, repo, name string, label *RepoLabel) error {
+	config.analytics.EditRepoLabel.Call(config, nil)
+	glog.Infof("Updating label %v of %v/%v", name, org, repo)
+	if config.DryRun {
+		return nil
+	}
+	_, err := config.labelsRequest("PATCH", labelURL(org, repo, name), label, nil)
+	return err
+}
+
+// DeleteRepoLabel deletes the label `name` from the org/repo repository,
+// and from all the issues that have it.
+func (config *Config) DeleteRepoLabel(org, repo, name string) error {
+	config.analytics.DeleteRepoLabel.Call(config, nil)
+	glog.Infof("Deleting label %v from %v/%v", name, org, repo)
+	if config.DryRun {
+		return nil
+	}
+	_, err := config.labelsRequest("DELETE", labelURL(org, repo, name), nil, nil)
+	return err
+}
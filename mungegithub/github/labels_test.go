@@ -0,0 +1,61 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"net/http"
+	"testing"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+)
+
+func TestRepoLabels(t *testing.T) {
+	client, server, mux := github_test.InitServer(t, nil, nil, nil, nil, nil, nil, nil)
+	defer server.Close()
+	config := &Config{}
+	config.SetClient(client)
+
+	mux.HandleFunc("/repos/o/r/labels", func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != mediaTypeLabelsPreview {
+			t.Errorf("Unexpected Accept header %q", accept)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"name": "sig/node", "color": "d2b48c", "description": "Node things"}]`))
+	})
+	edited := ""
+	mux.HandleFunc("/repos/o/r/labels/", func(w http.ResponseWriter, r *http.Request) {
+		edited = r.Method + " " + r.URL.EscapedPath()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	})
+
+	labels, err := config.ListRepoLabels("o", "r")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(labels) != 1 || *labels[0].Description != "Node things" {
+		t.Errorf("Unexpected labels %v", labels)
+	}
+
+	name := "sig/node"
+	if err := config.EditRepoLabel("o", "r", "sig/node area", &RepoLabel{Name: &name}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "PATCH /repos/o/r/labels/sig%2Fnode%20area"; edited != expected {
+		t.Errorf("Expected %q, got %q", expected, edited)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+)
+
+const (
+	// TODO: remove when the reviews API is out of preview, and use
+	// go-github once it supports it.
+	mediaTypeReviewsPreview = "application/vnd.github.black-cat-preview+json"
+
+	// ReviewApproved is the state of a review approving the PR
+	ReviewApproved = "APPROVED"
+	// ReviewChangesRequested is the state of a review requesting changes
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	// ReviewCommented is the state of a review with only comments
+	ReviewCommented = "COMMENTED"
+)
+
+// PullRequestReview is a review of a pull request. It is not part of the
+// vendored go-github yet.
+type PullRequestReview struct {
+	ID          *int         `json:"id,omitempty"`
+	User        *github.User `json:"user,omitempty"`
+	Body        *string      `json:"body,omitempty"`
+	State       *string      `json:"state,omitempty"`
+	SubmittedAt *time.Time   `json:"submitted_at,omitempty"`
+}
+
+// ListReviews returns all the reviews of the PR
+func (obj *MungeObject) ListReviews() ([]*PullRequestReview, error) {
+	if obj.reviews != nil {
+		return obj.reviews, nil
+	}
+	if !obj.IsPR() {
+		return []*PullRequestReview{}, nil
+	}
+
+	config := obj.config
+	prNum := obj.Number()
+	allReviews := []*PullRequestReview{}
+	page := 1
+	for {
+		glog.V(8).Infof("Fetching page %d of reviews for PR %d", page, prNum)
+		u := fmt.Sprintf("repos/%v/%v/pulls/%d/reviews?per_page=100&page=%d", config.Org, config.Project, prNum, page)
+		req, err := config.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", mediaTypeReviewsPreview)
+
+		reviews := []*PullRequestReview{}
+		response, err := config.client.Do(req, &reviews)
+		config.analytics.ListReviews.Call(config, response)
+		if err != nil {
+			return nil, err
+		}
+		allReviews = append(allReviews, reviews...)
+		if response.LastPage == 0 || response.LastPage <= page {
+			break
+		}
+		page++
+	}
+	obj.reviews = allReviews
+	return allReviews, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"net/http"
+	"testing"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+)
+
+func TestListReviews(t *testing.T) {
+	issue := github_test.Issue("user", 1, nil, true)
+	client, server, mux := github_test.InitServer(t, issue, nil, nil, nil, nil, nil, nil)
+	defer server.Close()
+	config := &Config{}
+	config.Org = "o"
+	config.Project = "r"
+	config.SetClient(client)
+
+	calls := 0
+	mux.HandleFunc("/repos/o/r/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if accept := r.Header.Get("Accept"); accept != mediaTypeReviewsPreview {
+			t.Errorf("Unexpected Accept header %q", accept)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{"id": 1, "user": {"login": "bob"}, "state": "APPROVED", "submitted_at": "2016-10-01T10:00:00Z"},
+			{"id": 2, "user": {"login": "alice"}, "state": "CHANGES_REQUESTED", "submitted_at": "2016-10-02T10:00:00Z"}
+		]`))
+	})
+
+	obj, err := config.GetObject(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		reviews, err := obj.ListReviews()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(reviews) != 2 || *reviews[0].State != ReviewApproved || *reviews[1].User.Login != "alice" {
+			t.Errorf("Unexpected reviews %v", reviews)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected reviews to be fetched once, got %d calls", calls)
+	}
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"time"
@@ -26,9 +27,11 @@ import (
 	github_util "k8s.io/contrib/mungegithub/github"
 	"k8s.io/contrib/mungegithub/mungers"
 	"k8s.io/contrib/mungegithub/reports"
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
 	utilflag "k8s.io/kubernetes/pkg/util/flag"
 
 	"github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 	"k8s.io/contrib/mungegithub/mungers/fsm"
 	"k8s.io/contrib/mungegithub/mungers/mungerutil"
@@ -46,18 +49,89 @@ type mungeConfig struct {
 	Once                bool
 	Period              time.Duration
 	StateMachineEnabled bool
+	StateMachineConfig  string
+	StateMachinePreset  string
+	StateMachineDot     bool
+	StateMachineStore   string
+	// StateMachineMetricLabels are the label prefixes used to break down
+	// state machine metrics.
+	StateMachineMetricLabels []string
+	StateMachineWorkers      int
+	StateMachineActionQPS    float64
+	StateMachineAddress      string
+	StateMachineStatusFile   string
 	features.Features
 }
 
 func addMungeFlags(config *mungeConfig, cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&config.Once, "once", false, "If true, run one loop and exit")
 	cmd.Flags().BoolVar(&config.StateMachineEnabled, "state-machine-enabled", false, "If true, run the state machine after all mungers are run.")
+	cmd.Flags().StringVar(&config.StateMachineConfig, "state-machine-config", "", "If set, YAML file describing the state machine to run instead of the built-in one.")
+	cmd.Flags().StringVar(&config.StateMachinePreset, "state-machine-preset", "", fmt.Sprintf("If set, name of a built-in state machine to run instead of the default one. One of %v", fsm.Presets()))
+	cmd.Flags().BoolVar(&config.StateMachineDot, "state-machine-dot", false, "If true, print the configured state machine as Graphviz DOT and exit.")
+	cmd.Flags().StringVar(&config.StateMachineStore, "state-machine-store", "", "If set, file where the state machine saves the state of each issue, so that only new events are evaluated.")
+	cmd.Flags().IntVar(&config.StateMachineWorkers, "state-machine-workers", 1, "Number of issues evaluated in parallel by the state machine.")
+	cmd.Flags().Float64Var(&config.StateMachineActionQPS, "state-machine-action-qps", 0, "If set, maximum number of actions per second taken by the state machine, shared by all workers.")
+	cmd.Flags().StringVar(&config.StateMachineAddress, "state-machine-address", "", "If set, address where the state machine dashboard is served. It is also available at /state-machine wherever another munger serves --address.")
+	cmd.Flags().StringVar(&config.StateMachineStatusFile, "state-machine-status-file", "", "If set, JSON file where the state of each issue is written after each pass. It is also served at /state-machine.json.")
+	cmd.Flags().StringSliceVar(&config.StateMachineMetricLabels, "state-machine-metric-labels", []string{"sig/"}, "Prefixes of the issue labels by which the time spent in each state is broken down. Metrics require --state-machine-store, and are served at /metrics with the dashboard.")
 	cmd.Flags().StringSliceVar(&config.PRMungersList, "pr-mungers", []string{}, "A list of pull request mungers to run")
 	cmd.Flags().StringSliceVar(&config.IssueReportsList, "issue-reports", []string{}, "A list of issue reports to run. If set, will run the reports and exit.")
 	cmd.Flags().DurationVar(&config.Period, "period", 10*time.Minute, "The period for running mungers")
 }
 
+// loadMachine returns the state machine configured with
+// --state-machine-config or --state-machine-preset, or nil if none is.
+func loadMachine(config *mungeConfig) (*fsm.Machine, error) {
+	if config.StateMachineConfig != "" && config.StateMachinePreset != "" {
+		return nil, fmt.Errorf("--state-machine-config and --state-machine-preset are mutually exclusive")
+	}
+	if config.StateMachineConfig != "" {
+		return fsm.LoadMachine(config.StateMachineConfig)
+	}
+	if config.StateMachinePreset != "" {
+		return fsm.LoadPreset(config.StateMachinePreset)
+	}
+	return nil, nil
+}
+
 func doMungers(config *mungeConfig) error {
+	computeState := fsm.ComputeState
+	var store *fsm.FileStore
+	machine, err := loadMachine(config)
+	if err != nil {
+		return err
+	}
+	if config.StateMachineEnabled && machine != nil {
+		for _, warning := range machine.Validate() {
+			glog.Warningf("State machine: %s", warning)
+		}
+		if config.StateMachineStore != "" {
+			if store, err = fsm.NewFileStore(config.StateMachineStore); err != nil {
+				return err
+			}
+			machine.Store = store
+			machine.Metrics = fsm.NewMetrics(config.StateMachineMetricLabels)
+			prometheus.MustRegister(machine.Metrics)
+			// Served with the dashboard rather than on the admin port,
+			// which only some mungers listen on.
+			http.Handle("/metrics", prometheus.Handler())
+		}
+		if config.StateMachineActionQPS > 0 {
+			machine.Limiter = fsm.NewRateLimiter(config.StateMachineActionQPS, utilclock.RealClock{})
+		}
+		machine.Dashboard = fsm.NewDashboard(utilclock.RealClock{})
+		http.Handle("/state-machine", machine.Dashboard)
+		http.HandleFunc("/state-machine.json", machine.Dashboard.ServeJSON)
+		if config.StateMachineAddress != "" {
+			go http.ListenAndServe(config.StateMachineAddress, nil)
+		}
+		start := machine.AsState()
+		computeState = func(obj *github_util.MungeObject) error {
+			return fsm.Run(start, obj)
+		}
+	}
+
 	for {
 		nextRunStartTime := time.Now().Add(config.Period)
 		glog.Infof("Running mungers")
@@ -71,8 +145,22 @@ func doMungers(config *mungeConfig) error {
 		}
 
 		if config.StateMachineEnabled {
-			if err := config.ForEachIssueDo(fsm.ComputeState); err != nil {
+			loopStart := time.Now()
+			if err := config.ForEachIssueDoParallel(config.StateMachineWorkers, computeState); err != nil {
 				glog.Errorf("Error computing state: %v", err)
+			} else if machine != nil && machine.Dashboard != nil {
+				// Issues that weren't listed have been closed
+				machine.Dashboard.Prune(loopStart)
+			}
+			if store != nil {
+				if err := store.Save(); err != nil {
+					glog.Errorf("Error saving state: %v", err)
+				}
+			}
+			if machine != nil && machine.Dashboard != nil && config.StateMachineStatusFile != "" {
+				if err := machine.Dashboard.WriteJSON(config.StateMachineStatusFile); err != nil {
+					glog.Errorf("Error writing state machine status: %v", err)
+				}
 			}
 		}
 
@@ -98,6 +186,16 @@ func main() {
 		Short: "A program to add labels, check tests, and generally mess with outstanding PRs",
 		RunE: func(_ *cobra.Command, _ []string) error {
 			glog.Info(mungerutil.PrettyString(config))
+			if config.StateMachineDot {
+				machine, err := loadMachine(config)
+				if err != nil {
+					return err
+				}
+				if machine == nil {
+					return fmt.Errorf("--state-machine-dot requires --state-machine-config or --state-machine-preset")
+				}
+				return machine.WriteDot(os.Stdout)
+			}
 			if err := config.PreExecute(); err != nil {
 				return err
 			}
@@ -19,10 +19,13 @@ package mungers
 import (
 	"fmt"
 	"os"
+	"path"
 	"regexp"
+	"time"
 
 	"k8s.io/contrib/mungegithub/features"
 	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
 	"k8s.io/kubernetes/pkg/util/yaml"
 
 	"github.com/golang/glog"
@@ -31,25 +34,38 @@ import (
 )
 
 const (
-	blockPathFormat = `Adding label:%s because PR changes docs prohibited to auto merge
+	blockedPathsLabel  = doNotMergeLabel + "/blocked-paths"
+	unblockPathCommand = "unblock-paths"
+	blockPathFormat    = `Adding label:%s because PR changes docs prohibited to auto merge
 See http://kubernetes.io/editdocs/ for information about editing docs`
 )
 
 var (
 	_             = fmt.Print
-	blockPathBody = fmt.Sprintf(blockPathFormat, doNotMergeLabel)
+	blockPathBody = fmt.Sprintf(blockPathFormat, blockedPathsLabel)
+	// blockPathOldBody was posted before the blocked-paths label existed.
+	// It refers to a label that is no longer used, so it is always stale.
+	blockPathOldBody = fmt.Sprintf(blockPathFormat, doNotMergeLabel)
 )
 
 type configBlockPath struct {
 	BlockRegexp      []string `json:"blockRegexp,omitempty" yaml:"blockRegexp,omitempty"`
 	DoNotBlockRegexp []string `json:"doNotBlockRegexp,omitempty" yaml:"doNotBlockRegexp,omitempty"`
+	// BlockGlob are patterns such as "docs/*", matched against the path
+	// of the files and of all their parent directories.
+	BlockGlob []string `json:"blockGlob,omitempty" yaml:"blockGlob,omitempty"`
+	// Approvers can unblock a PR by commenting /unblock-paths
+	Approvers []string `json:"approvers,omitempty" yaml:"approvers,omitempty"`
 }
 
-// BlockPath will add a label to block auto merge if a PR touches certain paths
+// BlockPath will add a label to block auto merge if a PR touches certain
+// paths, unless one of the approvers unblocked it since its last commit.
 type BlockPath struct {
 	Path             string
 	blockRegexp      []regexp.Regexp
 	doNotBlockRegexp []regexp.Regexp
+	blockGlob        []string
+	approvers        c.Matcher
 }
 
 func init() {
@@ -75,13 +91,13 @@ func (b *BlockPath) Initialize(config *github.Config, features *features.Feature
 	}
 	defer file.Close()
 
-	c := &configBlockPath{}
-	if err := yaml.NewYAMLToJSONDecoder(file).Decode(c); err != nil {
+	blockConfig := &configBlockPath{}
+	if err := yaml.NewYAMLToJSONDecoder(file).Decode(blockConfig); err != nil {
 		glog.Fatalf("Failed to decode the block-path config: %v", err)
 	}
 
 	b.blockRegexp = []regexp.Regexp{}
-	for _, str := range c.BlockRegexp {
+	for _, str := range blockConfig.BlockRegexp {
 		reg, err := regexp.Compile(str)
 		if err != nil {
 			return err
@@ -90,16 +106,32 @@ func (b *BlockPath) Initialize(config *github.Config, features *features.Feature
 	}
 
 	b.doNotBlockRegexp = []regexp.Regexp{}
-	for _, str := range c.DoNotBlockRegexp {
+	for _, str := range blockConfig.DoNotBlockRegexp {
 		reg, err := regexp.Compile(str)
 		if err != nil {
 			return err
 		}
 		b.doNotBlockRegexp = append(b.doNotBlockRegexp, *reg)
 	}
+
+	for _, glob := range blockConfig.BlockGlob {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid blockGlob %q: %v", glob, err)
+		}
+	}
+	b.blockGlob = blockConfig.BlockGlob
+	b.approvers = approversMatcher(blockConfig.Approvers)
 	return nil
 }
 
+func approversMatcher(approvers []string) c.Matcher {
+	logins := []c.Matcher{}
+	for _, login := range approvers {
+		logins = append(logins, c.AuthorLogin(login))
+	}
+	return c.Or(logins)
+}
+
 // EachLoop is called at the start of every munge loop
 func (b *BlockPath) EachLoop() error { return nil }
 
@@ -117,30 +149,72 @@ func matchesAny(path string, regs []regexp.Regexp) bool {
 	return false
 }
 
+// matchesAnyGlob returns true if the file, or one of its parent
+// directories, matches one of the globs.
+func matchesAnyGlob(file string, globs []string) bool {
+	for p := file; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		for _, glob := range globs {
+			if matched, _ := path.Match(glob, p); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBlocked returns true if changing the file requires an approver
+func (b *BlockPath) isBlocked(file string) bool {
+	if !matchesAny(file, b.blockRegexp) && !matchesAnyGlob(file, b.blockGlob) {
+		return false
+	}
+	return !matchesAny(file, b.doNotBlockRegexp)
+}
+
+// isUnblocked returns true if an approver commented /unblock-paths after
+// the last change of the PR.
+func (b *BlockPath) isUnblocked(comments []*githubapi.IssueComment, lastModified time.Time) bool {
+	unblock := c.FilterComments(comments, c.And([]c.Matcher{
+		c.CommandName(unblockPathCommand),
+		b.approvers,
+		c.CreatedAfter(lastModified),
+	}))
+	return !unblock.Empty()
+}
+
 // Munge is the workhorse the will actually make updates to the PR
 func (b *BlockPath) Munge(obj *github.MungeObject) {
 	if !obj.IsPR() {
 		return
 	}
 
-	if obj.HasLabel(doNotMergeLabel) {
-		return
-	}
-
 	files, err := obj.ListFiles()
 	if err != nil {
 		return
 	}
 
+	blocked := false
 	for _, f := range files {
-		if matchesAny(*f.Filename, b.blockRegexp) {
-			if matchesAny(*f.Filename, b.doNotBlockRegexp) {
-				continue
-			}
-			obj.WriteComment(blockPathBody)
-			obj.AddLabels([]string{doNotMergeLabel})
+		if f.Filename != nil && b.isBlocked(*f.Filename) {
+			blocked = true
+			break
+		}
+	}
+
+	if blocked {
+		lastModified := obj.LastModifiedTime()
+		comments, err := obj.ListComments()
+		if err != nil || lastModified == nil {
 			return
 		}
+		blocked = !b.isUnblocked(comments, *lastModified)
+	}
+
+	switch {
+	case blocked && !obj.HasLabel(blockedPathsLabel):
+		obj.WriteComment(blockPathBody)
+		obj.AddLabels([]string{blockedPathsLabel})
+	case !blocked && obj.HasLabel(blockedPathsLabel):
+		obj.RemoveLabel(blockedPathsLabel)
 	}
 }
 
@@ -148,10 +222,14 @@ func (b *BlockPath) isStaleComment(obj *github.MungeObject, comment *githubapi.I
 	if !mergeBotComment(comment) {
 		return false
 	}
+	if *comment.Body == blockPathOldBody {
+		glog.V(6).Infof("Found old BlockPath comment")
+		return true
+	}
 	if *comment.Body != blockPathBody {
 		return false
 	}
-	stale := !obj.HasLabel(doNotMergeLabel)
+	stale := !obj.HasLabel(blockedPathsLabel)
 	if stale {
 		glog.V(6).Infof("Found stale BlockPath comment")
 	}
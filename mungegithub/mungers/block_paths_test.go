@@ -0,0 +1,129 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestBlockPathIsBlocked(t *testing.T) {
+	b := &BlockPath{
+		blockRegexp:      []regexp.Regexp{*regexp.MustCompile("^docs/admin")},
+		doNotBlockRegexp: []regexp.Regexp{*regexp.MustCompile(`^docs/admin/kubelet\.md$`), *regexp.MustCompile(`^api/swagger-spec/README\.md$`)},
+		blockGlob:        []string{"api/swagger-spec", "*.pb.go"},
+	}
+
+	tests := []struct {
+		file     string
+		expected bool
+	}{
+		{file: "docs/admin/README.md", expected: true},
+		{file: "docs/admin/kubelet.md", expected: false},
+		{file: "api/swagger-spec/v1.json", expected: true},
+		{file: "api/swagger-spec/README.md", expected: false},
+		{file: "api/swagger-spec-old/v1.json", expected: false},
+		{file: "generated.pb.go", expected: true},
+		{file: "pkg/api/generated.pb.go", expected: false},
+		{file: "pkg/api/types.go", expected: false},
+	}
+	for _, test := range tests {
+		if blocked := b.isBlocked(test.file); blocked != test.expected {
+			t.Errorf("%s: expected blocked %v, got %v", test.file, test.expected, blocked)
+		}
+	}
+}
+
+func TestBlockPathIsUnblocked(t *testing.T) {
+	b := &BlockPath{approvers: approversMatcher([]string{"Approver"})}
+	lastModified := time.Unix(20, 0)
+
+	tests := []struct {
+		name     string
+		comments []*githubapi.IssueComment
+		expected bool
+	}{
+		{
+			name: "No command",
+		},
+		{
+			name:     "Unblocked by an approver",
+			comments: []*githubapi.IssueComment{github_test.Comment(1, "approver", time.Unix(30, 0), "/unblock-paths")},
+			expected: true,
+		},
+		{
+			name:     "Not an approver",
+			comments: []*githubapi.IssueComment{github_test.Comment(1, "user", time.Unix(30, 0), "/unblock-paths")},
+		},
+		{
+			name:     "Changed since it was unblocked",
+			comments: []*githubapi.IssueComment{github_test.Comment(1, "approver", time.Unix(10, 0), "/unblock-paths")},
+		},
+	}
+	for _, test := range tests {
+		if unblocked := b.isUnblocked(test.comments, lastModified); unblocked != test.expected {
+			t.Errorf("%s: expected unblocked %v, got %v", test.name, test.expected, unblocked)
+		}
+	}
+}
+
+func TestBlockPathStaleComments(t *testing.T) {
+	b := &BlockPath{}
+
+	tests := []struct {
+		name     string
+		labels   []string
+		body     string
+		expected bool
+	}{
+		{
+			name:   "Still blocked",
+			labels: []string{blockedPathsLabel},
+			body:   blockPathBody,
+		},
+		{
+			name:     "Not blocked anymore",
+			body:     blockPathBody,
+			expected: true,
+		},
+		{
+			name:     "Comment from before the blocked-paths label",
+			labels:   []string{blockedPathsLabel},
+			body:     blockPathOldBody,
+			expected: true,
+		},
+		{
+			name: "Another comment",
+			body: "Hello",
+		},
+	}
+	for _, test := range tests {
+		issue := github_test.Issue(botName, 1, test.labels, true)
+		obj := github.TestObject(&github.Config{}, issue, nil, nil, nil)
+		comments := []*githubapi.IssueComment{github_test.Comment(1, botName, time.Unix(10, 0), test.body)}
+		stale := b.StaleComments(obj, comments)
+		if (len(stale) == 1) != test.expected {
+			t.Errorf("%s: expected stale %v, got %v", test.name, test.expected, len(stale) == 1)
+		}
+	}
+}
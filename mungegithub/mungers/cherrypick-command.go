@@ -0,0 +1,272 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cherrypickCommandName = "cherrypick-command"
+	cherrypickCommand     = "cherrypick"
+	cherrypickNotifyName  = "CHERRYPICK"
+	// cherrypickPermission is the permission needed on the repository to /cherrypick
+	cherrypickPermission = "push"
+)
+
+var cherrypickBranchRe = regexp.MustCompile(`^release-[[:digit:]]+\.[[:digit:]]+$`)
+
+// CherrypickCommand handles `/cherrypick release-1.x` commands on merged
+// PRs. The merge commit is cherry-picked on top of the release branch and
+// a PR is opened if it applies cleanly. Otherwise the conflicting files
+// are reported on the original PR. The PR body is the one used by
+// hack/cherry_pick_pull.sh, so that cherrypick-auto-approve recognizes it.
+type CherrypickCommand struct {
+	config     *github.Config
+	permission c.Matcher
+	// gitCommand runs git in the clone of the repository
+	gitCommand func(args []string) ([]byte, error)
+}
+
+func init() {
+	RegisterMungerOrDie(&CherrypickCommand{})
+}
+
+// Name is the name usable in --pr-mungers
+func (cp *CherrypickCommand) Name() string { return cherrypickCommandName }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (cp *CherrypickCommand) RequiredFeatures() []string {
+	return []string{features.RepoFeatureName}
+}
+
+// Initialize will initialize the munger
+func (cp *CherrypickCommand) Initialize(config *github.Config, features *features.Features) error {
+	cp.config = config
+	cp.gitCommand = features.Repos.GitCommand
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (cp *CherrypickCommand) EachLoop() error {
+	pushUsers, _, err := cp.config.UsersWithAccess()
+	if err != nil {
+		return err
+	}
+	cp.permission = c.NewPermission(pushUsers, cherrypickPermission)
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (cp *CherrypickCommand) AddFlags(cmd *cobra.Command, config *github.Config) {}
+
+// pendingCherrypicks returns the branches requested with `/cherrypick`
+// by someone with the `permission`, which have not been answered yet.
+// A command can list several branches: `/cherrypick release-1.4 release-1.5`
+func pendingCherrypicks(comments []*githubapi.IssueComment, permission c.Matcher) []string {
+	commands := c.FilterComments(comments, c.And([]c.Matcher{
+		c.HumanActor(),
+		c.CommandName(cherrypickCommand),
+		permission,
+	}))
+	pending := []string{}
+	requested := map[string]bool{}
+	for _, comment := range commands {
+		for _, branch := range strings.Fields(c.ParseCommand(comment).Arguments) {
+			if requested[branch] {
+				continue
+			}
+			answered := c.FilterComments(comments, c.And([]c.Matcher{
+				c.MungerNotificationName(cherrypickNotifyName),
+				c.CreatedAfter(*comment.CreatedAt),
+				cherrypickNotificationBranch(branch),
+			}))
+			if answered.Empty() {
+				requested[branch] = true
+				pending = append(pending, branch)
+			}
+		}
+	}
+	return pending
+}
+
+// cherrypickNotificationBranch matches the notifications about `branch`
+type cherrypickNotificationBranch string
+
+func (b cherrypickNotificationBranch) Match(comment *githubapi.IssueComment) bool {
+	notif := c.ParseNotification(comment)
+	if notif == nil {
+		return false
+	}
+	fields := strings.Fields(notif.Arguments)
+	return len(fields) > 0 && fields[0] == string(b)
+}
+
+// cherrypickBranchName is the branch created for the cherry-pick, named
+// like the ones created by hack/cherry_pick_pull.sh
+func cherrypickBranchName(num int, branch string) string {
+	return fmt.Sprintf("automated-cherry-pick-of-#%d-upstream-%s", num, branch)
+}
+
+// pushURL is the remote the cherry-pick branches are pushed to
+func (cp *CherrypickCommand) pushURL() string {
+	return fmt.Sprintf("https://%s@github.com/%s/%s.git", cp.config.Token(), cp.config.Org, cp.config.Project)
+}
+
+// git runs a git command in the `dir` worktree. The token is removed
+// from the output, as it may end up in a comment.
+func (cp *CherrypickCommand) git(dir string, args ...string) (string, error) {
+	args = append([]string{"-C", dir, "-c", "user.name=" + botName, "-c", "user.email=" + botName + "@users.noreply.github.com"}, args...)
+	out, err := cp.gitCommand(args)
+	output := string(out)
+	if token := cp.config.Token(); token != "" {
+		output = strings.Replace(output, token, "<token>", -1)
+	}
+	return output, err
+}
+
+// cherrypick applies `sha` on top of `branch` and pushes the result to
+// `head`. It returns the conflicting files if the commit doesn't apply.
+func (cp *CherrypickCommand) cherrypick(sha, branch, head string) ([]string, error) {
+	tmp, err := ioutil.TempDir("", "cherrypick")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		os.RemoveAll(tmp)
+		cp.gitCommand([]string{"worktree", "prune"})
+	}()
+	dir := path.Join(tmp, branch)
+
+	if out, err := cp.git(".", "worktree", "add", "--detach", dir, "origin/"+branch); err != nil {
+		return nil, fmt.Errorf("unable to check out %s: %s", branch, out)
+	}
+	args := []string{"cherry-pick", "-x"}
+	parents, err := cp.git(dir, "rev-list", "--parents", "-n", "1", sha)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find commit %s: %s", sha, parents)
+	}
+	if len(strings.Fields(parents)) > 2 {
+		args = append(args, "-m1")
+	}
+	if _, err := cp.git(dir, append(args, sha)...); err != nil {
+		out, _ := cp.git(dir, "diff", "--name-only", "--diff-filter=U")
+		cp.git(dir, "cherry-pick", "--abort")
+		conflicts := strings.Fields(out)
+		if len(conflicts) == 0 {
+			return nil, fmt.Errorf("unable to cherry-pick %s", sha)
+		}
+		return conflicts, nil
+	}
+	if out, err := cp.git(dir, "push", "--force", cp.pushURL(), "HEAD:refs/heads/"+head); err != nil {
+		return nil, fmt.Errorf("unable to push %s: %s", head, out)
+	}
+	return nil, nil
+}
+
+// pick cherry-picks the PR on `branch`, and returns the notification
+// telling how it went.
+func (cp *CherrypickCommand) pick(obj *github.MungeObject, branch string) *c.Notification {
+	num := *obj.Issue.Number
+	notif := &c.Notification{Name: cherrypickNotifyName, Arguments: branch}
+
+	if !cherrypickBranchRe.MatchString(branch) {
+		notif.Arguments += " failed"
+		notif.Context = fmt.Sprintf("`%s` is not a release branch, expected something like `release-1.5`.", branch)
+		return notif
+	}
+	if _, err := cp.gitCommand([]string{"rev-parse", "--verify", "origin/" + branch}); err != nil {
+		notif.Arguments += " failed"
+		notif.Context = fmt.Sprintf("Branch `%s` doesn't exist.", branch)
+		return notif
+	}
+	sha := obj.MergeCommit()
+	if sha == nil {
+		glog.Errorf("Unable to get SHA of merged PR %d", num)
+		return nil
+	}
+
+	head := cherrypickBranchName(num, branch)
+	if cp.config.DryRun {
+		glog.Infof("Would cherry-pick %s from PR %d on %s, and open a PR from %s", *sha, num, branch, head)
+		return nil
+	}
+	conflicts, err := cp.cherrypick(*sha, branch, head)
+	if err != nil {
+		glog.Errorf("Failed to cherry-pick PR %d on %s: %v", num, branch, err)
+		return nil
+	}
+	if len(conflicts) != 0 {
+		notif.Arguments += " failed"
+		notif.Context = fmt.Sprintf(
+			"This PR doesn't apply cleanly on `%s`, it needs to be cherry-picked by hand. Conflicting files:\n- %s",
+			branch, strings.Join(conflicts, "\n- "))
+		return notif
+	}
+
+	title := ""
+	if obj.Issue.Title != nil {
+		title = *obj.Issue.Title
+	}
+	body := fmt.Sprintf("Cherry pick of #%d on %s.\n\n#%d: %s", num, branch, num, title)
+	pr, err := cp.config.NewPR(fmt.Sprintf("Automated cherry pick of #%d", num), body, head, branch)
+	if err != nil {
+		// Report the failure, so that the branch isn't pushed again on
+		// every loop.
+		glog.Errorf("Failed to open cherry-pick PR for %d on %s: %v", num, branch, err)
+		notif.Arguments += " failed"
+		notif.Context = fmt.Sprintf("This PR was cherry-picked on `%s` in branch `%s`, but the PR couldn't be opened. It needs to be opened by hand.", branch, head)
+		return notif
+	}
+	notif.Arguments += fmt.Sprintf(" #%d", *pr.Issue.Number)
+	notif.Context = fmt.Sprintf("Opened #%d to cherry-pick this PR on `%s`.", *pr.Issue.Number, branch)
+	return notif
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (cp *CherrypickCommand) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() || cp.permission == nil {
+		return
+	}
+	if merged, err := obj.IsMerged(); err != nil || !merged {
+		return
+	}
+
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	for _, branch := range pendingCherrypicks(comments, cp.permission) {
+		if notif := cp.pick(obj, branch); notif != nil {
+			notif.Post(obj)
+		}
+	}
+}
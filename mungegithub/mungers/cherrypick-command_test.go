@@ -0,0 +1,201 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/google/go-github/github"
+)
+
+func TestPendingCherrypicks(t *testing.T) {
+	permissions := map[string]bool{"push": true}
+	member := "member"
+	permission := c.NewPermission([]*github.User{
+		{Login: &member, Permissions: &permissions},
+	}, cherrypickPermission)
+
+	tests := []struct {
+		name     string
+		comments []*github.IssueComment
+		expected []string
+	}{
+		{
+			name:     "No command",
+			comments: []*github.IssueComment{github_test.Comment(1, "member", time.Unix(10, 0), "Looks good")},
+			expected: []string{},
+		},
+		{
+			name:     "Single branch",
+			comments: []*github.IssueComment{github_test.Comment(1, "member", time.Unix(10, 0), "/cherrypick release-1.5")},
+			expected: []string{"release-1.5"},
+		},
+		{
+			name:     "Several branches",
+			comments: []*github.IssueComment{github_test.Comment(1, "member", time.Unix(10, 0), "/cherrypick release-1.4 release-1.5")},
+			expected: []string{"release-1.4", "release-1.5"},
+		},
+		{
+			name:     "No permission",
+			comments: []*github.IssueComment{github_test.Comment(1, "user", time.Unix(10, 0), "/cherrypick release-1.5")},
+			expected: []string{},
+		},
+		{
+			name: "Already answered",
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "member", time.Unix(10, 0), "/cherrypick release-1.4 release-1.5"),
+				github_test.Comment(2, "k8s-merge-robot", time.Unix(20, 0), "[CHERRYPICK] release-1.5 #12"),
+			},
+			expected: []string{"release-1.4"},
+		},
+		{
+			name: "Failure is an answer too",
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "member", time.Unix(10, 0), "/cherrypick release-1.5"),
+				github_test.Comment(2, "k8s-merge-robot", time.Unix(20, 0), "[CHERRYPICK] release-1.5 failed"),
+			},
+			expected: []string{},
+		},
+		{
+			name: "Asked again after a failure",
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "member", time.Unix(10, 0), "/cherrypick release-1.5"),
+				github_test.Comment(2, "k8s-merge-robot", time.Unix(20, 0), "[CHERRYPICK] release-1.5 failed"),
+				github_test.Comment(3, "member", time.Unix(30, 0), "/cherrypick release-1.5"),
+			},
+			expected: []string{"release-1.5"},
+		},
+		{
+			name: "Asked twice",
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "member", time.Unix(10, 0), "/cherrypick release-1.5"),
+				github_test.Comment(2, "member", time.Unix(20, 0), "/cherrypick release-1.5"),
+			},
+			expected: []string{"release-1.5"},
+		},
+	}
+
+	for _, test := range tests {
+		pending := pendingCherrypicks(test.comments, permission)
+		if !reflect.DeepEqual(pending, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, pending)
+		}
+	}
+}
+
+func TestCherrypickPick(t *testing.T) {
+	merged := "merged"
+	sha := "abcdef"
+
+	tests := []struct {
+		name      string
+		branch    string
+		dryRun    bool
+		openPR    bool
+		expected  string
+		gitPushed bool
+	}{
+		{
+			name:     "Not a release branch",
+			branch:   "master",
+			expected: "master failed",
+		},
+		{
+			name:   "Dry run",
+			branch: "release-1.5",
+			dryRun: true,
+		},
+		{
+			name:      "PR opened",
+			branch:    "release-1.5",
+			openPR:    true,
+			expected:  "release-1.5 #2",
+			gitPushed: true,
+		},
+		{
+			name:      "PR can't be opened",
+			branch:    "release-1.5",
+			expected:  "release-1.5 failed",
+			gitPushed: true,
+		},
+	}
+
+	for _, test := range tests {
+		issue := github_test.Issue("author", 1, nil, true)
+		events := []*github.IssueEvent{{Event: &merged, CommitID: &sha}}
+		client, server, mux := github_test.InitServer(t, issue, ValidPR(), events, nil, nil, nil, nil)
+		if test.openPR {
+			mux.HandleFunc("/repos/o/r/pulls", func(w http.ResponseWriter, r *http.Request) {
+				data, _ := json.Marshal(github.PullRequest{Number: intPtr(2)})
+				w.WriteHeader(http.StatusCreated)
+				w.Write(data)
+			})
+			mux.HandleFunc("/repos/o/r/issues/2", func(w http.ResponseWriter, r *http.Request) {
+				data, _ := json.Marshal(github_test.Issue(botName, 2, nil, true))
+				w.WriteHeader(http.StatusOK)
+				w.Write(data)
+			})
+		}
+
+		config := &github_util.Config{}
+		config.Org = "o"
+		config.Project = "r"
+		config.DryRun = test.dryRun
+		config.SetClient(client)
+
+		pushed := false
+		cp := &CherrypickCommand{
+			config: config,
+			gitCommand: func(args []string) ([]byte, error) {
+				for _, arg := range args {
+					if arg == "worktree" || arg == "push" {
+						pushed = true
+					}
+				}
+				if len(args) > 0 && args[len(args)-1] == sha {
+					return []byte(sha + " parent"), nil
+				}
+				return nil, nil
+			},
+		}
+		obj, err := config.GetObject(1)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		notif := cp.pick(obj, test.branch)
+		server.Close()
+
+		arguments := ""
+		if notif != nil {
+			arguments = notif.Arguments
+		}
+		if arguments != test.expected {
+			t.Errorf("%s: expected notification %q, got %q", test.name, test.expected, arguments)
+		}
+		if pushed != test.gitPushed {
+			t.Errorf("%s: expected pushed %v, got %v", test.name, test.gitPushed, pushed)
+		}
+	}
+}
@@ -17,6 +17,12 @@ limitations under the License.
 package mungers
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"k8s.io/contrib/mungegithub/features"
@@ -26,6 +32,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
 )
 
 const (
@@ -55,10 +62,88 @@ Once you've signed, please reply here (e.g. "I signed it!") and we'll verify.  T
 	maxPings   = 3
 )
 
-// ClaMunger will check the CLA status of the PR and apply a label.
+// claAllowlistClient fetches the allowlist. A slow server must not block
+// the munge loop.
+var claAllowlistClient = &http.Client{Timeout: time.Minute}
+
+// ClaMunger will check the CLA status of the PR and apply a label. The
+// status is read from a status context, or from an allowlist of the
+// logins who signed the CLA.
 type ClaMunger struct {
-	CLAStatusContext string
-	pinger           *c.Pinger
+	CLAStatusContext    string
+	CLAAllowlist        string
+	CLAAllowlistRefresh time.Duration
+	pinger              *c.Pinger
+	allowlist           *claAllowlist
+}
+
+// claAllowlist is the list of logins who signed the CLA. It is read from
+// a file or a URL, and cached for `refresh`.
+type claAllowlist struct {
+	source  string
+	refresh time.Duration
+	loaded  time.Time
+	logins  sets.String
+}
+
+// readCLAAllowlist reads one login per line, ignoring case, empty lines
+// and comments starting with '#'.
+func readCLAAllowlist(r io.Reader) (sets.String, error) {
+	logins := sets.NewString()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		logins.Insert(strings.ToLower(line))
+	}
+	return logins, scanner.Err()
+}
+
+func (a *claAllowlist) fetch() (sets.String, error) {
+	if strings.HasPrefix(a.source, "http://") || strings.HasPrefix(a.source, "https://") {
+		resp, err := claAllowlistClient.Get(a.source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to get %s: %s", a.source, resp.Status)
+		}
+		return readCLAAllowlist(resp.Body)
+	}
+	file, err := os.Open(a.source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readCLAAllowlist(file)
+}
+
+// Update reloads the allowlist if the cached one is older than `refresh`.
+// The previous list is kept if it can't be loaded.
+func (a *claAllowlist) Update() error {
+	if a.logins != nil && time.Since(a.loaded) < a.refresh {
+		return nil
+	}
+	logins, err := a.fetch()
+	if err != nil {
+		return err
+	}
+	a.logins = logins
+	a.loaded = time.Now()
+	return nil
+}
+
+// Loaded returns true once the allowlist could be loaded
+func (a *claAllowlist) Loaded() bool {
+	return a.logins != nil
+}
+
+// Signed returns true if the login is in the allowlist
+func (a *claAllowlist) Signed(login string) bool {
+	return a.logins != nil && a.logins.Has(strings.ToLower(login))
 }
 
 var _ Munger = &ClaMunger{}
@@ -75,8 +160,15 @@ func (cla *ClaMunger) RequiredFeatures() []string { return []string{} }
 
 // Initialize will initialize the munger.
 func (cla *ClaMunger) Initialize(config *githubhelper.Config, features *features.Features) error {
-	if len(cla.CLAStatusContext) == 0 {
-		glog.Fatalf("No --cla-status-context flag set with cla munger.")
+	if len(cla.CLAStatusContext) == 0 && len(cla.CLAAllowlist) == 0 {
+		glog.Fatalf("No --cla-status-context or --cla-allowlist flag set with cla munger.")
+	}
+	if len(cla.CLAAllowlist) != 0 {
+		cla.allowlist = &claAllowlist{source: cla.CLAAllowlist, refresh: cla.CLAAllowlistRefresh}
+		// The source may be down for a while, try again on the next loop
+		if err := cla.allowlist.Update(); err != nil {
+			glog.Errorf("Failed to load CLA allowlist from %s: %v", cla.CLAAllowlist, err)
+		}
 	}
 
 	cla.pinger = c.NewPinger(claNagNotifyName).
@@ -87,12 +179,39 @@ func (cla *ClaMunger) Initialize(config *githubhelper.Config, features *features
 
 // EachLoop is called at the start of every munge loop
 func (cla *ClaMunger) EachLoop() error {
+	if cla.allowlist == nil {
+		return nil
+	}
+	if err := cla.allowlist.Update(); err != nil {
+		glog.Errorf("Failed to update CLA allowlist from %s: %v", cla.CLAAllowlist, err)
+	}
 	return nil
 }
 
 // AddFlags will add any request flags to the cobra `cmd`.
 func (cla *ClaMunger) AddFlags(cmd *cobra.Command, config *githubhelper.Config) {
 	cmd.Flags().StringVar(&cla.CLAStatusContext, "cla-status-context", "", "Status context to check to find if CLA is signed.")
+	cmd.Flags().StringVar(&cla.CLAAllowlist, "cla-allowlist", "", "File or URL listing the logins who signed the CLA, one per line. Used instead of --cla-status-context.")
+	cmd.Flags().DurationVar(&cla.CLAAllowlistRefresh, "cla-allowlist-refresh", time.Hour, "How long the --cla-allowlist is cached before it is loaded again.")
+}
+
+// status returns the state of the CLA for the PR, from the allowlist if
+// there is one.
+func (cla *ClaMunger) status(obj *githubhelper.MungeObject) string {
+	if cla.allowlist == nil {
+		return obj.GetStatusState([]string{cla.CLAStatusContext})
+	}
+	if !cla.allowlist.Loaded() {
+		// Don't blame anyone until we know who signed
+		return contextPending
+	}
+	if obj.Issue.User == nil || obj.Issue.User.Login == nil {
+		return contextError
+	}
+	if cla.allowlist.Signed(*obj.Issue.User.Login) {
+		return contextSuccess
+	}
+	return contextFailure
 }
 
 // Munge is unused by this munger.
@@ -105,7 +224,7 @@ func (cla *ClaMunger) Munge(obj *githubhelper.MungeObject) {
 		return
 	}
 
-	status := obj.GetStatusState([]string{cla.CLAStatusContext})
+	status := cla.status(obj)
 
 	// Check for pending status and exit.
 	if status == contextPending {
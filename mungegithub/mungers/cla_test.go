@@ -20,14 +20,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"runtime"
 	"testing"
+	"time"
 
 	github_util "k8s.io/contrib/mungegithub/github"
 	github_test "k8s.io/contrib/mungegithub/github/testing"
 	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
 
 	"github.com/google/go-github/github"
+	"k8s.io/kubernetes/pkg/util/sets"
 )
 
 const (
@@ -41,6 +44,8 @@ func TestCLAMunger(t *testing.T) {
 		name        string
 		issue       *github.Issue
 		status      *github.CombinedStatus
+		allowlist   []string
+		unreachable bool
 		mustHave    []string
 		mustNotHave []string
 	}{
@@ -100,6 +105,27 @@ func TestCLAMunger(t *testing.T) {
 			mustHave:    []string{},
 			mustNotHave: []string{cncfClaYesLabel, cncfClaNoLabel},
 		},
+		{
+			name:        "Author in the allowlist should add cncf/cla:yes label",
+			issue:       github_test.Issue("User1", 1, []string{cncfClaNoLabel}, true),
+			allowlist:   []string{"user1"},
+			mustHave:    []string{cncfClaYesLabel},
+			mustNotHave: []string{cncfClaNoLabel},
+		},
+		{
+			name:        "Author not in the allowlist should add cncf/cla:no label",
+			issue:       github_test.Issue("user2", 1, []string{}, true),
+			allowlist:   []string{"user1"},
+			mustHave:    []string{cncfClaNoLabel},
+			mustNotHave: []string{cncfClaYesLabel},
+		},
+		{
+			name:        "Allowlist not loaded yet should not apply labels",
+			issue:       github_test.Issue("user2", 1, []string{}, true),
+			unreachable: true,
+			mustHave:    []string{},
+			mustNotHave: []string{cncfClaYesLabel, cncfClaNoLabel},
+		},
 	}
 
 	for testNum, test := range tests {
@@ -129,6 +155,12 @@ func TestCLAMunger(t *testing.T) {
 			CLAStatusContext: claContext,
 			pinger:           c.NewPinger("[fake-ping]").SetDescription(""),
 		}
+		if test.allowlist != nil {
+			cla.allowlist = &claAllowlist{logins: sets.NewString(test.allowlist...), loaded: time.Now(), refresh: time.Hour}
+		}
+		if test.unreachable {
+			cla.allowlist = &claAllowlist{source: "/nonexistent/cla-allowlist", refresh: time.Hour}
+		}
 		obj, err := config.GetObject(*test.issue.Number)
 		if err != nil {
 			t.Fatalf("%v", err)
@@ -149,6 +181,61 @@ func TestCLAMunger(t *testing.T) {
 	}
 }
 
+func TestCLAAllowlistUpdate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, "# Signed the CLA\nUser1\n\n  user2  \n")
+	}))
+	defer server.Close()
+
+	allowlist := &claAllowlist{source: server.URL, refresh: time.Hour}
+	for i := 0; i < 2; i++ {
+		if err := allowlist.Update(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the allowlist to be cached, got %d requests", requests)
+	}
+	for login, expected := range map[string]bool{"user1": true, "USER2": true, "# Signed the CLA": false, "user3": false} {
+		if signed := allowlist.Signed(login); signed != expected {
+			t.Errorf("%q: expected signed %v, got %v", login, expected, signed)
+		}
+	}
+
+	// Keep the cached list if the source fails
+	allowlist.loaded = time.Time{}
+	allowlist.source = "/nonexistent/cla-allowlist"
+	if err := allowlist.Update(); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if !allowlist.Signed("user1") {
+		t.Errorf("Expected the previous allowlist to be kept")
+	}
+}
+
+func TestCLAAllowlistTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := claAllowlistClient
+	defer func() { claAllowlistClient = client }()
+	claAllowlistClient = &http.Client{Timeout: 10 * time.Millisecond}
+
+	allowlist := &claAllowlist{source: server.URL, refresh: time.Hour}
+	if err := allowlist.Update(); err == nil {
+		t.Errorf("Expected a timeout")
+	}
+	if allowlist.Loaded() {
+		t.Errorf("Expected the allowlist not to be loaded")
+	}
+}
+
 func setUpMockFunctions(mux *http.ServeMux, t *testing.T, issue *github.Issue) {
 	path := fmt.Sprintf("/repos/o/r/issue/%d/labels", *issue.Number)
 	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,51 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+var (
+	// supersededNotifications are the names of the notifications for
+	// which only the latest one is still relevant
+	supersededNotifications = []string{}
+)
+
+// CommentDeleterNotifications looks for bot notifications which were
+// superseded by a newer notification with the same name
+type CommentDeleterNotifications struct{}
+
+func init() {
+	RegisterStaleComments(CommentDeleterNotifications{})
+}
+
+// StaleComments returns a slice of comments which are stale
+func (CommentDeleterNotifications) StaleComments(obj *github.MungeObject, comments []*githubapi.IssueComment) []*githubapi.IssueComment {
+	out := []*githubapi.IssueComment{}
+	for _, name := range supersededNotifications {
+		notifications := c.FilterComments(comments, c.MungerNotificationName(name))
+		if notifications.Empty() {
+			continue
+		}
+		out = append(out, notifications[:len(notifications)-1]...)
+	}
+	return out
+}
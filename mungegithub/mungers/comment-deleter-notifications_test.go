@@ -0,0 +1,103 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+
+	githubapi "github.com/google/go-github/github"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+)
+
+func TestNotificationsStaleComments(t *testing.T) {
+	old := supersededNotifications
+	defer func() { supersededNotifications = old }()
+	supersededNotifications = []string{"NEEDS-REBASE", "TEST-RESULTS"}
+
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, botName, time.Unix(10, 0), "[NEEDS-REBASE] @user"),
+		github_test.Comment(2, botName, time.Unix(20, 0), "[TEST-RESULTS] failed"),
+		github_test.Comment(3, "user", time.Unix(30, 0), "[NEEDS-REBASE] not from the bot"),
+		github_test.Comment(4, botName, time.Unix(40, 0), "[needs-rebase] @user"),
+		github_test.Comment(5, botName, time.Unix(50, 0), "[CLA-PING] not superseded"),
+		github_test.Comment(6, botName, time.Unix(60, 0), "[CLA-PING] not superseded"),
+		github_test.Comment(7, botName, time.Unix(70, 0), "[TEST-RESULTS] passed"),
+	}
+	stale := CommentDeleterNotifications{}.StaleComments(nil, comments)
+	ids := []int{}
+	for _, comment := range stale {
+		ids = append(ids, *comment.ID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("Expected comments 1 and 2 to be stale, got %v", ids)
+	}
+}
+
+func TestCollapsedComments(t *testing.T) {
+	comment := github_test.Comment(1, botName, time.Unix(10, 0), "[NEEDS-REBASE] @user")
+	if isCollapsed(comment) {
+		t.Errorf("Comment shouldn't be collapsed: %q", *comment.Body)
+	}
+	comment = github_test.Comment(1, botName, time.Unix(10, 0), collapsedCommentPrefix+"\n\n[NEEDS-REBASE] @user\n</details>")
+	if !isCollapsed(comment) {
+		t.Errorf("Comment should be collapsed: %q", *comment.Body)
+	}
+}
+
+func TestCollapsedBody(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected string
+		notif    string
+	}{
+		{
+			body:     "[NEEDS-REBASE] @user\n\nPlease rebase.",
+			expected: "[NEEDS-REBASE] @user\n\n" + collapsedCommentPrefix + "\n\nPlease rebase.\n</details>",
+			notif:    "NEEDS-REBASE",
+		},
+		{
+			body:     "[NEEDS-REBASE] @user",
+			expected: "[NEEDS-REBASE] @user\n\n" + collapsedCommentPrefix + "\n\n\n</details>",
+			notif:    "NEEDS-REBASE",
+		},
+		{
+			body:     "Old test results",
+			expected: collapsedCommentPrefix + "\n\nOld test results\n</details>",
+		},
+	}
+	for _, test := range tests {
+		comment := github_test.Comment(1, botName, time.Unix(10, 0), test.body)
+		body := collapsedBody(comment)
+		if body != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.body, test.expected, body)
+		}
+		collapsed := github_test.Comment(1, botName, time.Unix(10, 0), body)
+		if !isCollapsed(collapsed) {
+			t.Errorf("%q: expected the comment to be collapsed", test.body)
+		}
+		notif := ""
+		if n := c.ParseNotification(collapsed); n != nil {
+			notif = n.Name
+		}
+		if notif != test.notif {
+			t.Errorf("%q: expected notification %q, got %q", test.body, test.notif, notif)
+		}
+	}
+}
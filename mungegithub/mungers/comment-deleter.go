@@ -17,8 +17,12 @@ limitations under the License.
 package mungers
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/contrib/mungegithub/features"
 	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
 
 	"github.com/golang/glog"
 	githubapi "github.com/google/go-github/github"
@@ -27,11 +31,19 @@ import (
 
 const (
 	commentDeleterName = "comment-deleter"
+	// collapsedCommentPrefix starts the body of stale comments that were
+	// collapsed instead of deleted.
+	collapsedCommentPrefix = "<details><summary>Outdated comment</summary>"
 )
 
 var (
 	_        = glog.Infof
 	deleters = []StaleComment{}
+	// issueDeleters also run on issues which are not PRs
+	issueDeleters = []StaleComment{}
+
+	// collapseStaleComments hides stale comments instead of deleting them
+	collapseStaleComments bool
 )
 
 // CommentDeleter looks for comments which are no longer useful
@@ -54,6 +66,12 @@ func RegisterStaleComments(s StaleComment) {
 	deleters = append(deleters, s)
 }
 
+// RegisterIssueStaleComments is like RegisterStaleComments, for a munger which
+// also comments on issues which are not PRs
+func RegisterIssueStaleComments(s StaleComment) {
+	issueDeleters = append(issueDeleters, s)
+}
+
 // Name is the name usable in --pr-mungers
 func (CommentDeleter) Name() string { return commentDeleterName }
 
@@ -67,7 +85,40 @@ func (CommentDeleter) Initialize(config *github.Config, features *features.Featu
 func (CommentDeleter) EachLoop() error { return nil }
 
 // AddFlags will add any request flags to the cobra `cmd`
-func (CommentDeleter) AddFlags(cmd *cobra.Command, config *github.Config) {}
+func (CommentDeleter) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().BoolVar(&collapseStaleComments, "collapse-stale-comments", false, "Collapse stale comments, so that they can still be read, instead of deleting them")
+	cmd.Flags().StringSliceVar(&supersededNotifications, "superseded-notifications", []string{}, "Names of the bot notifications (such as NEEDS-REBASE) of which only the latest is kept")
+}
+
+// isCollapsed returns true if the comment was already collapsed
+func isCollapsed(comment *githubapi.IssueComment) bool {
+	return strings.Contains(*comment.Body, collapsedCommentPrefix)
+}
+
+// collapsedBody hides the body of the comment in a closed <details> block.
+// The header of bot notifications is kept outside, so that mungers still
+// find their previous notifications.
+func collapsedBody(comment *githubapi.IssueComment) string {
+	header, body := "", *comment.Body
+	if c.ParseNotification(comment) != nil {
+		parts := strings.SplitN(body, "\n", 2)
+		header = parts[0] + "\n\n"
+		body = ""
+		if len(parts) == 2 {
+			body = strings.TrimSpace(parts[1])
+		}
+	}
+	return fmt.Sprintf("%s%s\n\n%s\n</details>", header, collapsedCommentPrefix, body)
+}
+
+// collapseComment collapses the comment, unless it already is: several
+// mungers may find the same comment stale.
+func collapseComment(obj *github.MungeObject, comment *githubapi.IssueComment) error {
+	if isCollapsed(comment) {
+		return nil
+	}
+	return obj.EditComment(comment, collapsedBody(comment))
+}
 
 func validComment(comment *githubapi.IssueComment) bool {
 	if comment.User == nil || comment.User.Login == nil {
@@ -84,7 +135,11 @@ func validComment(comment *githubapi.IssueComment) bool {
 
 // Munge is the workhorse the will actually make updates to the PR
 func (CommentDeleter) Munge(obj *github.MungeObject) {
-	if !obj.IsPR() {
+	staleDeleters := issueDeleters
+	if obj.IsPR() {
+		staleDeleters = append(append([]StaleComment{}, deleters...), issueDeleters...)
+	}
+	if len(staleDeleters) == 0 {
 		return
 	}
 
@@ -96,15 +151,19 @@ func (CommentDeleter) Munge(obj *github.MungeObject) {
 	validComments := []*githubapi.IssueComment{}
 	for i := range comments {
 		comment := comments[i]
-		if !validComment(comment) {
+		if !validComment(comment) || isCollapsed(comment) {
 			continue
 		}
 		validComments = append(validComments, comment)
 	}
-	for _, d := range deleters {
+	for _, d := range staleDeleters {
 		stale := d.StaleComments(obj, validComments)
 		for _, comment := range stale {
-			obj.DeleteComment(comment)
+			if collapseStaleComments {
+				collapseComment(obj, comment)
+			} else {
+				obj.DeleteComment(comment)
+			}
 		}
 	}
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+type fakeStaleComment struct {
+	called bool
+}
+
+func (f *fakeStaleComment) StaleComments(obj *github_util.MungeObject, comments []*github.IssueComment) []*github.IssueComment {
+	f.called = true
+	return nil
+}
+
+func TestCommentDeleterIssues(t *testing.T) {
+	savedDeleters, savedIssueDeleters := deleters, issueDeleters
+	defer func() {
+		deleters, issueDeleters = savedDeleters, savedIssueDeleters
+	}()
+
+	tests := []struct {
+		name         string
+		isPR         bool
+		prDeleter    bool
+		issueDeleter bool
+	}{
+		{
+			name:         "PR",
+			isPR:         true,
+			prDeleter:    true,
+			issueDeleter: true,
+		},
+		{
+			name:         "Issue",
+			isPR:         false,
+			prDeleter:    false,
+			issueDeleter: true,
+		},
+	}
+
+	for _, test := range tests {
+		prDeleter, issueDeleter := &fakeStaleComment{}, &fakeStaleComment{}
+		deleters = []StaleComment{prDeleter}
+		issueDeleters = []StaleComment{issueDeleter}
+
+		issue := github_test.Issue("author", 1, nil, test.isPR)
+		var pr *github.PullRequest
+		if test.isPR {
+			pr = ValidPR()
+		}
+		client, server, mux := github_test.InitServer(t, issue, pr, nil, nil, nil, nil, nil)
+		path := fmt.Sprintf("/repos/o/r/issues/%d/comments", *issue.Number)
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			data, err := json.Marshal([]*github.IssueComment{
+				github_test.Comment(1, "someone", time.Now(), "Hello"),
+			})
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		})
+
+		config := &github_util.Config{}
+		config.Org = "o"
+		config.Project = "r"
+		config.SetClient(client)
+
+		obj, err := config.GetObject(*issue.Number)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		CommentDeleter{}.Munge(obj)
+		if prDeleter.called != test.prDeleter {
+			t.Errorf("%s: expected PR deleter called to be %v, got %v", test.name, test.prDeleter, prDeleter.called)
+		}
+		if issueDeleter.called != test.issueDeleter {
+			t.Errorf("%s: expected issue deleter called to be %v, got %v", test.name, test.issueDeleter, issueDeleter.called)
+		}
+		server.Close()
+	}
+}
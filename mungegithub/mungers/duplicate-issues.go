@@ -0,0 +1,203 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/contrib/mungegithub/features"
+	mgh "k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+const (
+	duplicatesNotifyName = "POSSIBLE-DUPLICATES"
+	// duplicatesMaxCandidates is the number of candidates listed at most
+	duplicatesMaxCandidates = 5
+)
+
+var (
+	titleWordRE = regexp.MustCompile(`[[:alnum:]][[:alnum:]._/-]*[[:alnum:]]`)
+	// titleStopWords are too common to tell issues apart
+	titleStopWords = sets.NewString(
+		"the", "and", "for", "with", "not", "when", "does", "doesn't", "from",
+		"should", "can", "cannot", "can't", "are", "into", "after", "before",
+		"this", "that", "out", "fails", "failing", "failed", "error", "issue",
+	)
+)
+
+// DuplicateIssuesMunger looks for issues filed recently with a title
+// similar to the one of new issues, and lists them in a comment so that
+// duplicates are spotted before triage.
+type DuplicateIssuesMunger struct {
+	config    *mgh.Config
+	lookback  time.Duration
+	newAge    time.Duration
+	threshold float64
+	recent    []*github.Issue
+}
+
+func init() {
+	RegisterMungerOrDie(&DuplicateIssuesMunger{})
+}
+
+// Name is the name usable in --pr-mungers
+func (d *DuplicateIssuesMunger) Name() string { return "duplicate-issues" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (d *DuplicateIssuesMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (d *DuplicateIssuesMunger) Initialize(config *mgh.Config, features *features.Features) error {
+	if d.threshold <= 0 || d.threshold > 1 {
+		return fmt.Errorf("--duplicates-threshold must be in (0, 1], got %v", d.threshold)
+	}
+	d.config = config
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (d *DuplicateIssuesMunger) EachLoop() error {
+	since := time.Now().Add(-d.lookback)
+	issues, err := d.config.ListAllIssues(&github.IssueListByRepoOptions{
+		State: "all",
+		Since: since,
+	})
+	if err != nil {
+		return err
+	}
+	d.recent = []*github.Issue{}
+	for _, issue := range issues {
+		if issue.PullRequestLinks != nil || issue.CreatedAt == nil || issue.CreatedAt.Before(since) {
+			continue
+		}
+		d.recent = append(d.recent, issue)
+	}
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (d *DuplicateIssuesMunger) AddFlags(cmd *cobra.Command, config *mgh.Config) {
+	cmd.Flags().DurationVar(&d.lookback, "duplicates-lookback", 30*day, "How far back to look for issues similar to new ones")
+	cmd.Flags().DurationVar(&d.newAge, "duplicates-new-issue-age", 24*time.Hour, "Issues created more recently than this are checked for duplicates")
+	cmd.Flags().Float64Var(&d.threshold, "duplicates-threshold", 0.5, "Minimum share of title words in common for an issue to be listed as a possible duplicate")
+}
+
+// titleWords returns the significant words of an issue title
+func titleWords(title string) sets.String {
+	words := sets.NewString()
+	for _, word := range titleWordRE.FindAllString(strings.ToLower(title), -1) {
+		if len(word) > 2 && !titleStopWords.Has(word) {
+			words.Insert(word)
+		}
+	}
+	return words
+}
+
+// titleSimilarity is the share of words the titles have in common (Jaccard index)
+func titleSimilarity(a, b sets.String) float64 {
+	union := a.Union(b).Len()
+	if union == 0 {
+		return 0
+	}
+	return float64(a.Intersection(b).Len()) / float64(union)
+}
+
+type duplicateCandidate struct {
+	number     int
+	similarity float64
+}
+
+// findDuplicates returns the numbers of the `candidates` with a title
+// similar to the one of `issue`, most similar first. Only the issues opened
+// before `issue` (with a lower number) can be the original.
+func findDuplicates(issue *github.Issue, candidates []*github.Issue, threshold float64) []int {
+	if issue.Title == nil {
+		return nil
+	}
+	words := titleWords(*issue.Title)
+	found := []duplicateCandidate{}
+	for _, candidate := range candidates {
+		if candidate.Number == nil || candidate.Title == nil || *candidate.Number >= *issue.Number {
+			continue
+		}
+		if similarity := titleSimilarity(words, titleWords(*candidate.Title)); similarity >= threshold {
+			found = append(found, duplicateCandidate{*candidate.Number, similarity})
+		}
+	}
+	sort.Sort(bySimilarity(found))
+
+	numbers := []int{}
+	for i := 0; i < len(found) && i < duplicatesMaxCandidates; i++ {
+		numbers = append(numbers, found[i].number)
+	}
+	return numbers
+}
+
+type bySimilarity []duplicateCandidate
+
+func (s bySimilarity) Len() int      { return len(s) }
+func (s bySimilarity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySimilarity) Less(i, j int) bool {
+	if s[i].similarity != s[j].similarity {
+		return s[i].similarity > s[j].similarity
+	}
+	// Older issues first, they are more likely to be the original
+	return s[i].number < s[j].number
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (d *DuplicateIssuesMunger) Munge(obj *mgh.MungeObject) {
+	if obj.IsPR() || obj.Issue.CreatedAt == nil || time.Since(*obj.Issue.CreatedAt) > d.newAge {
+		return
+	}
+
+	duplicates := findDuplicates(obj.Issue, d.recent, d.threshold)
+	if len(duplicates) == 0 {
+		return
+	}
+
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	if !c.FilterComments(comments, c.MungerNotificationName(duplicatesNotifyName)).Empty() {
+		return
+	}
+
+	lines := []string{}
+	for _, number := range duplicates {
+		lines = append(lines, fmt.Sprintf("- #%d", number))
+	}
+	notif := c.Notification{
+		Name: duplicatesNotifyName,
+		Context: "These recent issues have a similar title, this may be a duplicate of one of them:\n" +
+			strings.Join(lines, "\n") +
+			"\n\nIf so, please close this issue and comment on the original one instead.",
+	}
+	notif.Post(obj)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"reflect"
+	"testing"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+func titledIssue(number int, title string) *github.Issue {
+	issue := github_test.Issue("user", number, nil, false)
+	issue.Title = &title
+	return issue
+}
+
+func TestFindDuplicates(t *testing.T) {
+	recent := []*github.Issue{
+		titledIssue(1, "kubectl logs hangs on terminated pods"),
+		titledIssue(2, "Kubelet fails to mount NFS volumes"),
+		titledIssue(3, "kubectl logs hangs for terminated pod"),
+		titledIssue(4, "Kubelet can't mount NFS volumes after restart"),
+		titledIssue(5, "Flaky test: e2e Networking"),
+	}
+
+	tests := []struct {
+		title    string
+		expected []int
+	}{
+		{
+			title:    "kubectl logs hangs on terminated pods",
+			expected: []int{1, 3},
+		},
+		{
+			title:    "NFS volumes fail to mount in kubelet",
+			expected: []int{2, 4},
+		},
+		{
+			title:    "Add a --watch flag to kubectl apply",
+			expected: []int{},
+		},
+		{
+			title:    "The and for with",
+			expected: []int{},
+		},
+	}
+
+	for _, test := range tests {
+		duplicates := findDuplicates(titledIssue(10, test.title), recent, 0.5)
+		if !reflect.DeepEqual(duplicates, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.title, test.expected, duplicates)
+		}
+	}
+
+	// An issue is not a duplicate of itself
+	if duplicates := findDuplicates(recent[2], recent, 0.5); !reflect.DeepEqual(duplicates, []int{1}) {
+		t.Errorf("Expected [1], got %v", duplicates)
+	}
+	// Nor of a newer issue, even if it is the only match
+	if duplicates := findDuplicates(recent[0], recent, 0.5); !reflect.DeepEqual(duplicates, []int{}) {
+		t.Errorf("Expected no duplicates, got %v", duplicates)
+	}
+}
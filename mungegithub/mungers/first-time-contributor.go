@@ -0,0 +1,156 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"strings"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+const (
+	firstTimeContributorLabel = "first-time-contributor"
+	welcomeNotifyName         = "WELCOME"
+)
+
+// FirstTimeContributorMunger welcomes the authors of their first PR to
+// the repository with a comment, and labels the PR first-time-contributor.
+// Previous PRs are looked up once per author, and remembered.
+type FirstTimeContributorMunger struct {
+	Message string
+	config  *github.Config
+	// collaborators have push access, they don't need a welcome
+	collaborators sets.String
+	// contributors are known to have sent a PR before
+	contributors sets.String
+}
+
+func init() {
+	RegisterMungerOrDie(&FirstTimeContributorMunger{})
+}
+
+// Name is the name usable in --pr-mungers
+func (f *FirstTimeContributorMunger) Name() string { return "first-time-contributor" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (f *FirstTimeContributorMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (f *FirstTimeContributorMunger) Initialize(config *github.Config, features *features.Features) error {
+	f.config = config
+	f.contributors = sets.NewString()
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (f *FirstTimeContributorMunger) EachLoop() error {
+	pushUsers, _, err := f.config.UsersWithAccess()
+	if err != nil {
+		return err
+	}
+	f.collaborators = sets.NewString()
+	for _, user := range pushUsers {
+		if user.Login != nil {
+			f.collaborators.Insert(strings.ToLower(*user.Login))
+		}
+	}
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (f *FirstTimeContributorMunger) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().StringVar(&f.Message, "first-time-contributor-message",
+		"Thanks for your first pull request, and welcome! Please have a look at the [contributor guide](https://github.com/kubernetes/kubernetes/blob/master/CONTRIBUTING.md) and sign the CLA if you haven't yet. A reviewer will be assigned shortly.",
+		"Welcome comment posted on the first PR of a contributor")
+}
+
+// isFirstPR tells if the PR `number` is the first one in `authored`, the
+// issues and PRs of its author.
+func isFirstPR(number int, authored []*githubapi.Issue) bool {
+	for _, issue := range authored {
+		if issue.PullRequestLinks != nil && issue.Number != nil && *issue.Number < number {
+			return false
+		}
+	}
+	return true
+}
+
+// firstTime tells if `login` opened the PR `number` as their first PR
+func (f *FirstTimeContributorMunger) firstTime(login string, number int) (bool, error) {
+	if f.contributors.Has(login) {
+		return false, nil
+	}
+	authored, err := f.config.ListAllIssues(&githubapi.IssueListByRepoOptions{
+		Creator: login,
+		State:   "all",
+	})
+	if err != nil {
+		return false, err
+	}
+	if !isFirstPR(number, authored) {
+		f.contributors.Insert(login)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (f *FirstTimeContributorMunger) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() || f.collaborators == nil || obj.HasLabel(firstTimeContributorLabel) {
+		return
+	}
+	if obj.Issue.User == nil || obj.Issue.User.Login == nil {
+		return
+	}
+	login := strings.ToLower(*obj.Issue.User.Login)
+	if f.collaborators.Has(login) || login == botName || login == jenkinsBotName {
+		return
+	}
+
+	first, err := f.firstTime(login, *obj.Issue.Number)
+	if err != nil {
+		glog.Errorf("Failed to list the PRs of %s: %v", login, err)
+		return
+	}
+	if !first {
+		return
+	}
+
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	if c.FilterComments(comments, c.MungerNotificationName(welcomeNotifyName)).Empty() {
+		notif := c.Notification{
+			Name:      welcomeNotifyName,
+			Arguments: "@" + *obj.Issue.User.Login,
+			Context:   f.Message,
+		}
+		if err := notif.Post(obj); err != nil {
+			return
+		}
+	}
+	obj.AddLabel(firstTimeContributorLabel)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+func TestIsFirstPR(t *testing.T) {
+	tests := []struct {
+		name     string
+		authored []*github.Issue
+		expected bool
+	}{
+		{
+			name:     "Only this PR",
+			authored: []*github.Issue{github_test.Issue("user", 10, nil, true)},
+			expected: true,
+		},
+		{
+			name: "Issues filed before don't count",
+			authored: []*github.Issue{
+				github_test.Issue("user", 5, nil, false),
+				github_test.Issue("user", 10, nil, true),
+			},
+			expected: true,
+		},
+		{
+			name: "Later PRs don't count",
+			authored: []*github.Issue{
+				github_test.Issue("user", 10, nil, true),
+				github_test.Issue("user", 12, nil, true),
+			},
+			expected: true,
+		},
+		{
+			name: "Previous PR",
+			authored: []*github.Issue{
+				github_test.Issue("user", 7, nil, true),
+				github_test.Issue("user", 10, nil, true),
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		if first := isFirstPR(10, test.authored); first != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, first)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"k8s.io/contrib/mungegithub/github"
+)
+
+// Action is something that must be done on an issue to reflect its
+// state.
+type Action interface {
+	Do(obj *github.MungeObject) error
+	String() string
+}
+
+// AddLabel adds a label to the issue
+type AddLabel string
+
+// Do adds the label, unless the issue already has it
+func (a AddLabel) Do(obj *github.MungeObject) error {
+	if obj.HasLabel(string(a)) {
+		return nil
+	}
+	return obj.AddLabel(string(a))
+}
+
+// String describes the action
+func (a AddLabel) String() string {
+	return "add label " + string(a)
+}
+
+// RemoveLabel removes a label from the issue
+type RemoveLabel string
+
+// Do removes the label, if the issue has it
+func (r RemoveLabel) Do(obj *github.MungeObject) error {
+	if !obj.HasLabel(string(r)) {
+		return nil
+	}
+	return obj.RemoveLabel(string(r))
+}
+
+// String describes the action
+func (r RemoveLabel) String() string {
+	return "remove label " + string(r)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+const (
+	// AuthorRole allows the author of the issue to run a command.
+	AuthorRole = "author"
+	// AssigneeRole allows the assignees of the issue to run a command.
+	AssigneeRole = "assignee"
+)
+
+// CommandGuard matches a slash-command such as "/lgtm" or "/hold cancel"
+// posted by a human. If Allowed is not empty, the author of the command
+// must be one of the listed logins, or have one of the roles (AuthorRole,
+// AssigneeRole) on the issue.
+type CommandGuard struct {
+	Command string
+	Allowed []string
+}
+
+// Match returns true if the item is the command, by an allowed author
+func (c CommandGuard) Match(item *Item) bool {
+	if item.Comment == nil {
+		return false
+	}
+	if !(comment.And{comment.HumanActor(), commandMatcher(c.Command)}).Match(item.Comment) {
+		return false
+	}
+	if len(c.Allowed) == 0 {
+		return true
+	}
+	return isAllowed(item.Issue, *item.Comment.User.Login, c.Allowed)
+}
+
+// isAllowed returns true if the user is one of the allowed logins, or has
+// one of the allowed roles on the issue.
+func isAllowed(issue *githubapi.Issue, login string, allowed []string) bool {
+	for _, role := range allowed {
+		if hasRole(issue, login, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRole(issue *githubapi.Issue, login, role string) bool {
+	switch role {
+	case AuthorRole:
+		return issue != nil && issue.User != nil && sameLogin(issue.User.Login, login)
+	case AssigneeRole:
+		if issue == nil {
+			return false
+		}
+		for _, assignee := range issue.Assignees {
+			if assignee != nil && sameLogin(assignee.Login, login) {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.ToLower(role) == strings.ToLower(login)
+}
+
+func sameLogin(a *string, b string) bool {
+	return a != nil && strings.ToLower(*a) == strings.ToLower(b)
+}
+
+func init() {
+	RegisterGuard("command-by", func(args []string) (Guard, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("command-by expects a command and at least one allowed user, got %d arguments", len(args))
+		}
+		return CommandGuard{Command: args[0], Allowed: args[1:]}, nil
+	})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestCommandGuard(t *testing.T) {
+	issue := github_test.Issue("author", 1, nil, true)
+	assignee := "Assignee"
+	issue.Assignees = []*githubapi.User{{Login: &assignee}}
+
+	tests := []struct {
+		name    string
+		guard   CommandGuard
+		login   string
+		body    string
+		matches bool
+	}{
+		{"Anyone", CommandGuard{Command: "hold"}, "user", "/hold", true},
+		{"Other command", CommandGuard{Command: "hold"}, "user", "/lgtm", false},
+		{"Arguments must match", CommandGuard{Command: "hold"}, "user", "/hold cancel", false},
+		{"With arguments", CommandGuard{Command: "hold cancel"}, "user", "/HOLD Cancel", true},
+		{"Bots are ignored", CommandGuard{Command: "hold"}, "k8s-merge-robot", "/hold", false},
+		{"Author", CommandGuard{Command: "ready", Allowed: []string{AuthorRole}}, "author", "/ready", true},
+		{"Not the author", CommandGuard{Command: "ready", Allowed: []string{AuthorRole}}, "user", "/ready", false},
+		{"Assignee", CommandGuard{Command: "lgtm", Allowed: []string{AssigneeRole}}, "assignee", "/lgtm", true},
+		{"Not an assignee", CommandGuard{Command: "lgtm", Allowed: []string{AssigneeRole}}, "author", "/lgtm", false},
+		{"Login", CommandGuard{Command: "approve", Allowed: []string{"author", "Root"}}, "root", "/approve", true},
+	}
+	for _, test := range tests {
+		item := &Item{Comment: github_test.Comment(1, test.login, time.Unix(10, 0), test.body), Issue: issue}
+		if matches := test.guard.Match(item); matches != test.matches {
+			t.Errorf("%s: expected match %v, got %v", test.name, test.matches, matches)
+		}
+	}
+}
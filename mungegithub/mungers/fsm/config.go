@@ -0,0 +1,284 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/matchers/event"
+	"k8s.io/kubernetes/pkg/util/yaml"
+)
+
+// GuardFactory creates a Guard from the arguments given in the
+// configuration file.
+type GuardFactory func(args []string) (Guard, error)
+
+// ActionFactory creates an Action from the arguments given in the
+// configuration file.
+type ActionFactory func(args []string) (Action, error)
+
+var (
+	guardFactories  = map[string]GuardFactory{}
+	actionFactories = map[string]ActionFactory{}
+)
+
+// RegisterGuard makes a guard available by name in configuration files.
+func RegisterGuard(name string, factory GuardFactory) error {
+	if _, found := guardFactories[name]; found {
+		return fmt.Errorf("a guard with that name (%s) already exists", name)
+	}
+	guardFactories[name] = factory
+	return nil
+}
+
+// RegisterAction makes an action available by name in configuration files.
+func RegisterAction(name string, factory ActionFactory) error {
+	if _, found := actionFactories[name]; found {
+		return fmt.Errorf("an action with that name (%s) already exists", name)
+	}
+	actionFactories[name] = factory
+	return nil
+}
+
+func expectArgs(name string, args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("%s expects %d argument(s), got %d", name, n, len(args))
+	}
+	return nil
+}
+
+func init() {
+	RegisterGuard("label-added", func(args []string) (Guard, error) {
+		if err := expectArgs("label-added", args, 1); err != nil {
+			return nil, err
+		}
+		return EventGuard{event.And{event.AddLabel{}, event.LabelName(args[0])}}, nil
+	})
+	RegisterGuard("label-removed", func(args []string) (Guard, error) {
+		if err := expectArgs("label-removed", args, 1); err != nil {
+			return nil, err
+		}
+		return EventGuard{event.And{event.RemoveLabel{}, event.LabelName(args[0])}}, nil
+	})
+	RegisterGuard("command", func(args []string) (Guard, error) {
+		if err := expectArgs("command", args, 1); err != nil {
+			return nil, err
+		}
+		return CommandGuard{Command: args[0]}, nil
+	})
+	RegisterGuard("human-comment", func(args []string) (Guard, error) {
+		if err := expectArgs("human-comment", args, 0); err != nil {
+			return nil, err
+		}
+		return CommentGuard{comment.HumanActor()}, nil
+	})
+
+	RegisterAction("add-label", func(args []string) (Action, error) {
+		if err := expectArgs("add-label", args, 1); err != nil {
+			return nil, err
+		}
+		return AddLabel(args[0]), nil
+	})
+	RegisterAction("remove-label", func(args []string) (Action, error) {
+		if err := expectArgs("remove-label", args, 1); err != nil {
+			return nil, err
+		}
+		return RemoveLabel(args[0]), nil
+	})
+}
+
+// commandMatcher matches a command line such as "lgtm" or "lgtm cancel".
+// The arguments of the command must be exactly the same (ignoring case).
+func commandMatcher(line string) comment.Matcher {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return comment.False{}
+	}
+	args := regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(strings.Join(fields[1:], " ")) + `$`)
+	return comment.And{
+		comment.CommandName(fields[0]),
+		comment.CommandArguments(*args),
+	}
+}
+
+// reference points to a registered guard or action. Guards can instead
+// be a Comment or Event matcher.
+type reference struct {
+	Name    string       `json:"name,omitempty" yaml:"name,omitempty"`
+	Args    []string     `json:"args,omitempty" yaml:"args,omitempty"`
+	Comment *matcherFile `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Event   *matcherFile `json:"event,omitempty" yaml:"event,omitempty"`
+}
+
+// String displays the reference as name(arg1, arg2)
+func (r reference) String() string {
+	switch {
+	case r.Comment != nil:
+		return "comment(" + r.Comment.String() + ")"
+	case r.Event != nil:
+		return "event(" + r.Event.String() + ")"
+	}
+	return r.Name + "(" + strings.Join(r.Args, ", ") + ")"
+}
+
+type stateFile struct {
+	Name    string      `json:"name" yaml:"name"`
+	Parent  string      `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Initial string      `json:"initial,omitempty" yaml:"initial,omitempty"`
+	Labels  []string    `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Actions []reference `json:"actions,omitempty" yaml:"actions,omitempty"`
+	OnEnter []reference `json:"onEnter,omitempty" yaml:"onEnter,omitempty"`
+	OnExit  []reference `json:"onExit,omitempty" yaml:"onExit,omitempty"`
+	// Activity is a guard matching items that reset the timers.
+	Activity *reference `json:"activity,omitempty" yaml:"activity,omitempty"`
+}
+
+type transitionFile struct {
+	From  string     `json:"from" yaml:"from"`
+	To    string     `json:"to" yaml:"to"`
+	Guard *reference `json:"guard,omitempty" yaml:"guard,omitempty"`
+	// After is a duration such as "72h", for timed transitions.
+	After string `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+type machineFile struct {
+	Initial     string           `json:"initial" yaml:"initial"`
+	States      []stateFile      `json:"states" yaml:"states"`
+	Transitions []transitionFile `json:"transitions" yaml:"transitions"`
+}
+
+// LoadMachine reads the machine definition from a YAML file.
+func LoadMachine(path string) (*Machine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadMachine(file)
+}
+
+// ReadMachine decodes a YAML machine definition. Guards and actions
+// must reference names registered with RegisterGuard and RegisterAction.
+func ReadMachine(r io.Reader) (*Machine, error) {
+	c := &machineFile{}
+	if err := yaml.NewYAMLToJSONDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+
+	m := &Machine{Initial: c.Initial}
+	for _, s := range c.States {
+		state := StateConfig{Name: s.Name, Parent: s.Parent, Initial: s.Initial, Labels: s.Labels}
+		for _, a := range s.Actions {
+			factory, ok := actionFactories[a.Name]
+			if !ok {
+				return nil, fmt.Errorf("state %q: unknown action %q", s.Name, a.Name)
+			}
+			action, err := factory(a.Args)
+			if err != nil {
+				return nil, fmt.Errorf("state %q: %v", s.Name, err)
+			}
+			state.Actions = append(state.Actions, action)
+		}
+		if s.Activity != nil {
+			activity, err := readGuard(*s.Activity)
+			if err != nil {
+				return nil, fmt.Errorf("state %q: %v", s.Name, err)
+			}
+			state.Activity = activity
+		}
+		var err error
+		if state.OnEnter, err = readHooks(s.OnEnter); err != nil {
+			return nil, fmt.Errorf("state %q: %v", s.Name, err)
+		}
+		if state.OnExit, err = readHooks(s.OnExit); err != nil {
+			return nil, fmt.Errorf("state %q: %v", s.Name, err)
+		}
+		m.States = append(m.States, state)
+	}
+	for _, t := range c.Transitions {
+		transition := Transition{From: t.From, To: t.To}
+		if t.Guard != nil {
+			guard, err := readGuard(*t.Guard)
+			if err != nil {
+				return nil, fmt.Errorf("transition %q -> %q: %v", t.From, t.To, err)
+			}
+			transition.Guard = guard
+			transition.Description = t.Guard.String()
+		}
+		if t.After != "" {
+			after, err := time.ParseDuration(t.After)
+			if err != nil {
+				return nil, fmt.Errorf("transition %q -> %q: %v", t.From, t.To, err)
+			}
+			transition.After = after
+			transition.Description = "after " + t.After
+		}
+		m.Transitions = append(m.Transitions, transition)
+	}
+
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func readGuard(ref reference) (Guard, error) {
+	if ref.Comment != nil || ref.Event != nil {
+		if ref.Name != "" || (ref.Comment != nil && ref.Event != nil) {
+			return nil, fmt.Errorf("guard must have exactly one of name, comment, event")
+		}
+		if ref.Comment != nil {
+			matcher, err := readCommentMatcher(*ref.Comment)
+			if err != nil {
+				return nil, err
+			}
+			return CommentGuard{matcher}, nil
+		}
+		matcher, err := readEventMatcher(*ref.Event)
+		if err != nil {
+			return nil, err
+		}
+		return EventGuard{matcher}, nil
+	}
+	factory, ok := guardFactories[ref.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown guard %q", ref.Name)
+	}
+	return factory(ref.Args)
+}
+
+func readHooks(refs []reference) ([]Hook, error) {
+	hooks := []Hook{}
+	for _, ref := range refs {
+		factory, ok := hookFactories[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown hook %q", ref.Name)
+		}
+		hook, err := factory(ref.Args)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+const testConfig = `
+initial: needs-review
+states:
+- name: needs-review
+  labels: [state/needs-review]
+- name: needs-rebase
+  labels: [state/needs-rebase]
+  actions:
+  - name: add-label
+    args: [do-not-merge]
+- name: lgtm
+transitions:
+- from: needs-review
+  to: lgtm
+  guard:
+    name: label-added
+    args: [lgtm]
+- from: needs-review
+  to: needs-rebase
+  guard:
+    name: command
+    args: [rebase]
+- from: needs-rebase
+  to: needs-review
+  guard:
+    name: label-removed
+    args: [needs-rebase]
+`
+
+func TestReadMachine(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.Initial != "needs-review" || len(m.States) != 3 || len(m.Transitions) != 3 {
+		t.Fatalf("Unexpected machine: %+v", m)
+	}
+
+	issue := github_test.Issue("user", 1, nil, true)
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", time.Unix(10, 0), "/rebase"),
+	}
+	result, err := m.Process(issue, nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "needs-rebase" {
+		t.Errorf("Expected needs-rebase state, got %q", result.State)
+	}
+	if len(result.Actions) != 2 || result.Actions[1] != AddLabel("do-not-merge") {
+		t.Errorf("Unexpected actions: %v", result.Actions)
+	}
+}
+
+func TestReadMachineErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "unknown guard",
+			config: "initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a, guard: {name: unknown}}]",
+		},
+		{
+			name:   "bad guard arguments",
+			config: "initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a, guard: {name: label-added}}]",
+		},
+		{
+			name:   "unknown action",
+			config: "initial: a\nstates: [{name: a, actions: [{name: unknown}]}]",
+		},
+		{
+			name:   "unknown state",
+			config: "initial: b\nstates: [{name: a}]",
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := ReadMachine(strings.NewReader(test.config)); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
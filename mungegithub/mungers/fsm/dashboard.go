@@ -0,0 +1,174 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+)
+
+// IssueStatus is the state of an issue, as last computed by the machine.
+type IssueStatus struct {
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	URL            string    `json:"url"`
+	State          string    `json:"state"`
+	EnteredAt      time.Time `json:"enteredAt"`
+	LastTransition string    `json:"lastTransition,omitempty"`
+	// PendingActions are the actions of the last pass that were not
+	// applied because one of them failed.
+	PendingActions []string `json:"pendingActions,omitempty"`
+
+	updated time.Time
+}
+
+// Dashboard keeps the status of each issue seen by the machine, and
+// serves them as an HTML page so that contributors can see what the bot
+// is waiting for.
+type Dashboard struct {
+	lock   sync.RWMutex
+	clock  utilclock.Clock
+	issues map[int]*IssueStatus
+}
+
+// NewDashboard creates an empty dashboard.
+func NewDashboard(clock utilclock.Clock) *Dashboard {
+	return &Dashboard{
+		clock:  clock,
+		issues: map[int]*IssueStatus{},
+	}
+}
+
+// Update records the state computed for the issue, and the actions
+// that are still pending.
+func (d *Dashboard) Update(issue *githubapi.Issue, result *Result, pending []Action) {
+	status := &IssueStatus{
+		Number:         *issue.Number,
+		State:          result.State,
+		EnteredAt:      result.Checkpoint.EnteredAt,
+		LastTransition: result.Checkpoint.LastTransition,
+		updated:        d.clock.Now(),
+	}
+	for _, action := range pending {
+		status.PendingActions = append(status.PendingActions, action.String())
+	}
+	if issue.Title != nil {
+		status.Title = *issue.Title
+	}
+	if issue.HTMLURL != nil {
+		status.URL = *issue.HTMLURL
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.issues[status.Number] = status
+}
+
+// Prune forgets the issues that haven't been updated since the given
+// time, typically because they have been closed.
+func (d *Dashboard) Prune(before time.Time) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for number, status := range d.issues {
+		if status.updated.Before(before) {
+			delete(d.issues, number)
+		}
+	}
+}
+
+// List returns the status of all issues, by number.
+func (d *Dashboard) List() []IssueStatus {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	list := []IssueStatus{}
+	for _, status := range d.issues {
+		list = append(list, *status)
+	}
+	sort.Sort(byNumber(list))
+	return list
+}
+
+type byNumber []IssueStatus
+
+func (b byNumber) Len() int           { return len(b) }
+func (b byNumber) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byNumber) Less(i, j int) bool { return b[i].Number < b[j].Number }
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head><title>State machine</title></head>
+<body>
+<table>
+<tr><th>Issue</th><th>Title</th><th>State</th><th>Time in state</th><th>Last transition</th></tr>
+{{range .}}<tr><td><a href="{{.URL}}">#{{.Number}}</a></td><td>{{.Title}}</td><td>{{.State}}</td><td>{{.Duration}}</td><td>{{.LastTransition}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// WriteJSON writes the status of all issues to a JSON file, so that
+// other tools can consume it.
+func (d *Dashboard) WriteJSON(path string) error {
+	data, err := json.Marshal(d.List())
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// ServeJSON serves the status of all issues as JSON
+func (d *Dashboard) ServeJSON(res http.ResponseWriter, req *http.Request) {
+	data, err := json.Marshal(d.List())
+	if err != nil {
+		glog.Errorf("Unable to marshal the state machine status: %v", err)
+		res.Header().Set("Content-type", "text/plain")
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	res.Write(data)
+}
+
+// ServeHTTP serves the dashboard
+func (d *Dashboard) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	type row struct {
+		IssueStatus
+		Duration string
+	}
+	now := d.clock.Now()
+	rows := []row{}
+	for _, status := range d.List() {
+		r := row{IssueStatus: status, Duration: "unknown"}
+		if !status.EnteredAt.IsZero() {
+			r.Duration = (now.Sub(status.EnteredAt) / time.Minute * time.Minute).String()
+		}
+		rows = append(rows, r)
+	}
+	res.Header().Set("Content-type", "text/html")
+	if err := dashboardTemplate.Execute(res, rows); err != nil {
+		glog.Errorf("Failed to render the state machine dashboard: %v", err)
+	}
+}
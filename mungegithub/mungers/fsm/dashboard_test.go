@@ -0,0 +1,110 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestDashboard(t *testing.T) {
+	m := testMachine()
+	clock := utilclock.NewFakeClock(time.Unix(3600, 0))
+	dashboard := NewDashboard(clock)
+
+	issue := github_test.Issue("user", 2, nil, true)
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", time.Unix(600, 0), "/changes"),
+	}
+	result, err := m.Process(issue, nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dashboard.Update(issue, result, []Action{AddLabel("state/changes")})
+
+	clock.Step(time.Minute)
+	other := github_test.Issue("user", 1, nil, true)
+	result, err = m.Process(other, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dashboard.Update(other, result, nil)
+
+	list := dashboard.List()
+	if len(list) != 2 || list[0].Number != 1 || list[1].Number != 2 {
+		t.Fatalf("Expected issues 1 and 2, got %v", list)
+	}
+	if list[1].State != "changes" || !list[1].EnteredAt.Equal(time.Unix(600, 0)) {
+		t.Errorf("Unexpected status %+v", list[1])
+	}
+
+	res := httptest.NewRecorder()
+	dashboard.ServeHTTP(res, &http.Request{})
+	for _, expected := range []string{
+		`<a href="Issue%20URL">#2</a>`,
+		`<td>changes</td><td>51m0s</td><td>review -&gt; changes  on comment by user: &#34;/changes&#34;</td>`,
+		`<td>review</td><td>unknown</td>`,
+	} {
+		if !strings.Contains(res.Body.String(), expected) {
+			t.Errorf("Expected %q in:\n%s", expected, res.Body.String())
+		}
+	}
+
+	res = httptest.NewRecorder()
+	dashboard.ServeJSON(res, &http.Request{})
+	decoded := []IssueStatus{}
+	if err := json.Unmarshal(res.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(decoded) != 2 || !reflect.DeepEqual(decoded[1].PendingActions, []string{"add label state/changes"}) ||
+		!decoded[1].EnteredAt.Equal(time.Unix(600, 0)) {
+		t.Errorf("Unexpected JSON %s", res.Body.String())
+	}
+
+	dir, err := ioutil.TempDir("", "fsm")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "status.json")
+	if err := dashboard.WriteJSON(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, err := ioutil.ReadFile(path); err != nil || !bytes.Equal(data, res.Body.Bytes()) {
+		t.Errorf("Expected the file to contain %s, got %s (%v)", res.Body.String(), data, err)
+	}
+
+	// Issue 2 wasn't updated since
+	dashboard.Prune(clock.Now())
+	if list := dashboard.List(); len(list) != 1 || list[0].Number != 1 {
+		t.Errorf("Expected only issue 1, got %v", list)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDot writes the machine as a Graphviz DOT graph. The initial state
+// is drawn with a double circle, composite states are drawn as clusters,
+// and transitions are labeled with their guard description.
+func (m *Machine) WriteDot(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "digraph fsm {")
+	fmt.Fprintln(buf, "\trankdir=LR;")
+	fmt.Fprintln(buf, "\tcompound=true;")
+	m.writeDotChildren(buf, "", "\t")
+	for _, t := range m.Transitions {
+		// Edges must link leaves, clipped to the cluster of composite states.
+		attrs := fmt.Sprintf("label=%q", t.Description)
+		if m.isComposite(t.From) {
+			attrs += fmt.Sprintf(", ltail=%q", "cluster_"+t.From)
+		}
+		if m.isComposite(t.To) {
+			attrs += fmt.Sprintf(", lhead=%q", "cluster_"+t.To)
+		}
+		fmt.Fprintf(buf, "\t%q -> %q [%s];\n", m.leaf(t.From), m.leaf(t.To), attrs)
+	}
+	fmt.Fprintln(buf, "}")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeDotChildren writes the states whose parent is `parent`.
+func (m *Machine) writeDotChildren(buf *bytes.Buffer, parent, indent string) {
+	for _, s := range m.States {
+		if s.Parent != parent {
+			continue
+		}
+		label := s.Name
+		if len(s.Labels) != 0 {
+			label += "\n" + strings.Join(s.Labels, "\n")
+		}
+		if m.isComposite(s.Name) {
+			fmt.Fprintf(buf, "%ssubgraph %q {\n", indent, "cluster_"+s.Name)
+			fmt.Fprintf(buf, "%s\tlabel=%q;\n", indent, label)
+			m.writeDotChildren(buf, s.Name, indent+"\t")
+			fmt.Fprintf(buf, "%s}\n", indent)
+			continue
+		}
+		shape := "circle"
+		if s.Name == m.leaf(m.Initial) {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(buf, "%s%q [shape=%s, label=%q];\n", indent, s.Name, shape, label)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := m.WriteDot(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dot := buf.String()
+
+	expected := []string{
+		"digraph fsm {",
+		`"needs-review" [shape=doublecircle, label="needs-review\nstate/needs-review"];`,
+		`"lgtm" [shape=circle, label="lgtm"];`,
+		`"needs-review" -> "lgtm" [label="label-added(lgtm)"];`,
+		`"needs-rebase" -> "needs-review" [label="label-removed(needs-rebase)"];`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Errorf("Expected %q in:\n%s", e, dot)
+		}
+	}
+}
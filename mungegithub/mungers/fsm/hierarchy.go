@@ -0,0 +1,121 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+)
+
+// States can be nested: a composite state is the Parent of other states,
+// and the machine is always in a leaf state. Transitions leaving a
+// composite state apply to all of its descendants, and transitions
+// entering it go to its Initial child.
+
+// isComposite returns true if the state has children.
+func (m *Machine) isComposite(name string) bool {
+	for _, s := range m.States {
+		if s.Parent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ancestors returns the state followed by its parent, grandparent, etc.
+func (m *Machine) ancestors(name string) []string {
+	out := []string{}
+	for name != "" && len(out) <= len(m.States) {
+		out = append(out, name)
+		s, ok := m.GetState(name)
+		if !ok {
+			break
+		}
+		name = s.Parent
+	}
+	return out
+}
+
+// isDescendant returns true if state is ancestor, or one of its
+// descendants.
+func (m *Machine) isDescendant(state, ancestor string) bool {
+	for _, a := range m.ancestors(state) {
+		if a == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// leaf follows the Initial child of composite states until it reaches a
+// leaf state.
+func (m *Machine) leaf(name string) string {
+	for i := 0; i <= len(m.States) && m.isComposite(name); i++ {
+		s, _ := m.GetState(name)
+		name = s.Initial
+	}
+	return name
+}
+
+func (m *Machine) checkHierarchy() error {
+	for _, s := range m.States {
+		if s.Parent != "" {
+			if _, ok := m.GetState(s.Parent); !ok {
+				return fmt.Errorf("state %q has unknown parent %q", s.Name, s.Parent)
+			}
+		}
+		if len(m.ancestors(s.Name)) > len(m.States) {
+			return fmt.Errorf("state %q is its own ancestor", s.Name)
+		}
+		if m.isComposite(s.Name) {
+			child, ok := m.GetState(s.Initial)
+			if !ok || child.Parent != s.Name {
+				return fmt.Errorf("composite state %q must have one of its children as initial state", s.Name)
+			}
+		} else if s.Initial != "" {
+			return fmt.Errorf("state %q has an initial state but no children", s.Name)
+		}
+	}
+	return nil
+}
+
+// exitedAndEntered returns the states left (innermost first) and entered
+// (outermost first) when moving from one leaf to another. Moving to the
+// same leaf leaves and re-enters it.
+func (m *Machine) exitedAndEntered(from, to string) (exited, entered []string) {
+	fromAncestors := m.ancestors(from)
+	toAncestors := m.ancestors(to)
+
+	common := map[string]bool{}
+	for _, a := range fromAncestors {
+		for _, b := range toAncestors {
+			if a == b && a != from && a != to {
+				common[a] = true
+			}
+		}
+	}
+	for _, a := range fromAncestors {
+		if !common[a] {
+			exited = append(exited, a)
+		}
+	}
+	for i := len(toAncestors) - 1; i >= 0; i-- {
+		if !common[toAncestors[i]] {
+			entered = append(entered, toAncestors[i])
+		}
+	}
+	return exited, entered
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+const testHierarchyConfig = `
+initial: in-review
+states:
+- name: in-review
+  initial: awaiting-reviewer
+  labels: [in-review]
+- name: awaiting-reviewer
+  parent: in-review
+  labels: [awaiting-reviewer]
+- name: awaiting-author
+  parent: in-review
+  labels: [awaiting-author]
+- name: closed
+transitions:
+- from: awaiting-reviewer
+  to: awaiting-author
+  guard: {name: command, args: [changes]}
+- from: awaiting-author
+  to: awaiting-reviewer
+  guard: {name: command, args: [ready]}
+# Shared by both children
+- from: in-review
+  to: closed
+  guard: {name: label-added, args: [lgtm]}
+- from: closed
+  to: in-review
+  guard: {name: label-removed, args: [lgtm]}
+`
+
+func TestHierarchy(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testHierarchyConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		labels   []string
+		events   []*githubapi.IssueEvent
+		comments []*githubapi.IssueComment
+		state    string
+		actions  []Action
+	}{
+		{
+			name:    "Starts in initial child",
+			state:   "awaiting-reviewer",
+			actions: []Action{AddLabel("in-review"), AddLabel("awaiting-reviewer")},
+		},
+		{
+			name:   "Transition between siblings",
+			labels: []string{"in-review", "awaiting-reviewer"},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "user", time.Unix(10, 0), "/changes"),
+			},
+			state:   "awaiting-author",
+			actions: []Action{AddLabel("awaiting-author"), RemoveLabel("awaiting-reviewer")},
+		},
+		{
+			name:   "Parent transition applies to children",
+			labels: []string{"in-review", "awaiting-author"},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "user", time.Unix(10, 0), "/changes"),
+			},
+			events:  []*githubapi.IssueEvent{labelEvent("labeled", "lgtm", 20)},
+			state:   "closed",
+			actions: []Action{RemoveLabel("in-review"), RemoveLabel("awaiting-author")},
+		},
+		{
+			name: "Entering parent enters initial child",
+			events: []*githubapi.IssueEvent{
+				labelEvent("labeled", "lgtm", 20),
+				labelEvent("unlabeled", "lgtm", 30),
+			},
+			state:   "awaiting-reviewer",
+			actions: []Action{AddLabel("in-review"), AddLabel("awaiting-reviewer")},
+		},
+	}
+
+	for _, test := range tests {
+		result, err := m.Process(github_test.Issue("user", 1, test.labels, true), test.events, test.comments)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.State != test.state {
+			t.Errorf("%s: expected state %q, got %q", test.name, test.state, result.State)
+		}
+		if !reflect.DeepEqual(result.Actions, test.actions) {
+			t.Errorf("%s: expected actions %v, got %v", test.name, test.actions, result.Actions)
+		}
+	}
+}
+
+func TestExitedAndEntered(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testHierarchyConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		from, to        string
+		exited, entered []string
+	}{
+		{"awaiting-reviewer", "awaiting-author", []string{"awaiting-reviewer"}, []string{"awaiting-author"}},
+		{"awaiting-author", "closed", []string{"awaiting-author", "in-review"}, []string{"closed"}},
+		{"closed", "awaiting-reviewer", []string{"closed"}, []string{"in-review", "awaiting-reviewer"}},
+		{"closed", "closed", []string{"closed"}, []string{"closed"}},
+	}
+	for _, test := range tests {
+		exited, entered := m.exitedAndEntered(test.from, test.to)
+		if !reflect.DeepEqual(exited, test.exited) || !reflect.DeepEqual(entered, test.entered) {
+			t.Errorf("%s -> %s: expected %v/%v, got %v/%v", test.from, test.to, test.exited, test.entered, exited, entered)
+		}
+	}
+}
+
+func TestHierarchyErrors(t *testing.T) {
+	tests := []string{
+		"initial: a\nstates: [{name: a, parent: b}]",
+		"initial: a\nstates: [{name: a, initial: b}, {name: b}]",
+		"initial: a\nstates: [{name: a, parent: b, initial: b}, {name: b, parent: a, initial: a}]",
+	}
+	for _, config := range tests {
+		if _, err := ReadMachine(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected an error for %q", config)
+		}
+	}
+}
+
+func TestHierarchyDot(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testHierarchyConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := m.WriteDot(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{
+		`subgraph "cluster_in-review" {`,
+		`"awaiting-reviewer" [shape=doublecircle`,
+		`"awaiting-reviewer" -> "closed" [label="label-added(lgtm)", ltail="cluster_in-review"];`,
+		`"closed" -> "awaiting-reviewer" [label="label-removed(lgtm)", lhead="cluster_in-review"];`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(buf.String(), e) {
+			t.Errorf("Expected %q in:\n%s", e, buf.String())
+		}
+	}
+}
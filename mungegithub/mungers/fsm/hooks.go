@@ -0,0 +1,224 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/contrib/mungegithub/github"
+	"k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/matchers/event"
+)
+
+// Hook is run when an issue enters or leaves a state. Since the history
+// of an issue can be evaluated more than once, a hook must check that it
+// hasn't already been run since the time of the transition.
+type Hook interface {
+	Run(obj *github.MungeObject, since time.Time) error
+	String() string
+}
+
+// hookAction is the Action of running a hook for a given transition.
+type hookAction struct {
+	hook  Hook
+	since time.Time
+}
+
+// Do runs the hook
+func (h hookAction) Do(obj *github.MungeObject) error {
+	return h.hook.Run(obj, h.since)
+}
+
+// String describes the action
+func (h hookAction) String() string {
+	return h.hook.String()
+}
+
+// hookActions returns the actions for the exit and enter hooks of each
+// step, in order. Composite states that contain both ends of a step are
+// neither left nor entered.
+func (m *Machine) hookActions(steps []Step) []Action {
+	actions := []Action{}
+	for _, step := range steps {
+		since := step.Time
+		exited, entered := m.exitedAndEntered(step.From, step.To)
+		for _, name := range exited {
+			if s, ok := m.GetState(name); ok {
+				for _, h := range s.OnExit {
+					actions = append(actions, hookAction{hook: h, since: since})
+				}
+			}
+		}
+		for _, name := range entered {
+			if s, ok := m.GetState(name); ok {
+				for _, h := range s.OnEnter {
+					actions = append(actions, hookAction{hook: h, since: since})
+				}
+			}
+		}
+	}
+	return actions
+}
+
+// LabelHook adds a label, unless it has been added since the transition.
+type LabelHook string
+
+// Run adds the label
+func (l LabelHook) Run(obj *github.MungeObject, since time.Time) error {
+	if obj.HasLabel(string(l)) {
+		return nil
+	}
+	events, err := obj.GetEvents()
+	if err != nil {
+		return err
+	}
+	if !event.FilterEvents(events, event.And{
+		event.AddLabel{},
+		event.LabelName(l),
+		event.CreatedAfter(since),
+	}).Empty() {
+		return nil
+	}
+	return obj.AddLabel(string(l))
+}
+
+// String describes the hook
+func (l LabelHook) String() string {
+	return "add label " + string(l)
+}
+
+// UnlabelHook removes a label, unless it has been removed since the
+// transition.
+type UnlabelHook string
+
+// Run removes the label
+func (u UnlabelHook) Run(obj *github.MungeObject, since time.Time) error {
+	if !obj.HasLabel(string(u)) {
+		return nil
+	}
+	events, err := obj.GetEvents()
+	if err != nil {
+		return err
+	}
+	if !event.FilterEvents(events, event.And{
+		event.RemoveLabel{},
+		event.LabelName(u),
+		event.CreatedAfter(since),
+	}).Empty() {
+		return nil
+	}
+	return obj.RemoveLabel(string(u))
+}
+
+// String describes the hook
+func (u UnlabelHook) String() string {
+	return "remove label " + string(u)
+}
+
+// NotifyHook posts a notification, unless the bot has posted a
+// notification with the same name since the transition.
+type NotifyHook comment.Notification
+
+// Run posts the notification
+func (n NotifyHook) Run(obj *github.MungeObject, since time.Time) error {
+	comments, err := obj.ListComments()
+	if err != nil {
+		return err
+	}
+	if !comment.FilterComments(comments, comment.And{
+		comment.MungerNotificationName(n.Name),
+		comment.CreatedAfter(since),
+	}).Empty() {
+		return nil
+	}
+	return comment.Notification(n).Post(obj)
+}
+
+// String describes the hook
+func (n NotifyHook) String() string {
+	return "notify " + strings.ToUpper(n.Name)
+}
+
+// AssignHook assigns the issue to a user, unless they are already
+// assigned.
+type AssignHook string
+
+// Run assigns the user
+func (a AssignHook) Run(obj *github.MungeObject, since time.Time) error {
+	for _, assignee := range obj.Issue.Assignees {
+		if assignee != nil && assignee.Login != nil && strings.ToLower(*assignee.Login) == strings.ToLower(string(a)) {
+			return nil
+		}
+	}
+	return obj.AssignPR(string(a))
+}
+
+// String describes the hook
+func (a AssignHook) String() string {
+	return "assign " + string(a)
+}
+
+// HookFactory creates a Hook from the arguments given in the
+// configuration file.
+type HookFactory func(args []string) (Hook, error)
+
+var hookFactories = map[string]HookFactory{}
+
+// RegisterHook makes a hook available by name in configuration files.
+func RegisterHook(name string, factory HookFactory) error {
+	if _, found := hookFactories[name]; found {
+		return fmt.Errorf("a hook with that name (%s) already exists", name)
+	}
+	hookFactories[name] = factory
+	return nil
+}
+
+func init() {
+	RegisterHook("add-label", func(args []string) (Hook, error) {
+		if err := expectArgs("add-label", args, 1); err != nil {
+			return nil, err
+		}
+		return LabelHook(args[0]), nil
+	})
+	RegisterHook("remove-label", func(args []string) (Hook, error) {
+		if err := expectArgs("remove-label", args, 1); err != nil {
+			return nil, err
+		}
+		return UnlabelHook(args[0]), nil
+	})
+	RegisterHook("notify", func(args []string) (Hook, error) {
+		if len(args) < 1 || len(args) > 3 {
+			return nil, fmt.Errorf("notify expects 1 to 3 arguments, got %d", len(args))
+		}
+		n := NotifyHook{Name: args[0]}
+		if len(args) > 1 {
+			n.Arguments = args[1]
+		}
+		if len(args) > 2 {
+			n.Context = args[2]
+		}
+		return n, nil
+	})
+	RegisterHook("assign", func(args []string) (Hook, error) {
+		if err := expectArgs("assign", args, 1); err != nil {
+			return nil, err
+		}
+		return AssignHook(args[0]), nil
+	})
+}
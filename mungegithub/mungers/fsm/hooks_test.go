@@ -0,0 +1,117 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestHookActions(t *testing.T) {
+	m := testMachine()
+	m.States[0].OnExit = []Hook{UnlabelHook("reviewing")}
+	m.States[1].OnEnter = []Hook{NotifyHook{Name: "CHANGES"}}
+
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", time.Unix(10, 0), "/changes"),
+	}
+	result, err := m.Process(github_test.Issue("user", 1, []string{"state/changes"}, true), nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Action{
+		hookAction{hook: UnlabelHook("reviewing"), since: time.Unix(10, 0)},
+		hookAction{hook: NotifyHook{Name: "CHANGES"}, since: time.Unix(10, 0)},
+	}
+	if len(result.Actions) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, result.Actions)
+	}
+	for i := range expected {
+		if result.Actions[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], result.Actions[i])
+		}
+	}
+}
+
+func TestNotifyHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []*githubapi.IssueComment
+		posted   bool
+	}{
+		{
+			name:   "Post when there is no notification",
+			posted: true,
+		},
+		{
+			name: "Post when the notification is older than the transition",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "k8s-merge-robot", time.Unix(5, 0), "[CHANGES]"),
+			},
+			posted: true,
+		},
+		{
+			name: "Don't post twice",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "k8s-merge-robot", time.Unix(15, 0), "[CHANGES]"),
+			},
+			posted: false,
+		},
+	}
+
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, nil, true)
+		client, server, mux := github_test.InitServer(t, issue, nil, nil, nil, nil, nil, nil)
+		posted := false
+		mux.HandleFunc("/repos/o/r/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				posted = true
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("{}"))
+				return
+			}
+			data, err := json.Marshal(test.comments)
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		})
+
+		config := &github_util.Config{Org: "o", Project: "r"}
+		config.SetClient(client)
+		obj, err := config.GetObject(1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if err := (NotifyHook{Name: "CHANGES"}).Run(obj, time.Unix(10, 0)); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if posted != test.posted {
+			t.Errorf("%s: expected posted=%v, got %v", test.name, test.posted, posted)
+		}
+		server.Close()
+	}
+}
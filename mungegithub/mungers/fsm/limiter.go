@@ -0,0 +1,57 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"sync"
+	"time"
+
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+)
+
+// RateLimiter spaces out actions, so that issues evaluated in parallel
+// share a single budget of github API writes.
+type RateLimiter struct {
+	lock     sync.Mutex
+	clock    utilclock.Clock
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter allows qps actions per second.
+func NewRateLimiter(qps float64, clock utilclock.Clock) *RateLimiter {
+	return &RateLimiter{
+		clock:    clock,
+		interval: time.Duration(float64(time.Second) / qps),
+	}
+}
+
+// Wait blocks until the next action is allowed.
+func (r *RateLimiter) Wait() {
+	r.lock.Lock()
+	now := r.clock.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.lock.Unlock()
+
+	if wait > 0 {
+		r.clock.Sleep(wait)
+	}
+}
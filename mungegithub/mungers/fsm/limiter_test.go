@@ -0,0 +1,48 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"testing"
+	"time"
+
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+)
+
+func TestRateLimiter(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := utilclock.NewFakeClock(start)
+	limiter := NewRateLimiter(2, clock)
+
+	// The first action goes through immediately, the next ones wait
+	// half a second each.
+	for i := 0; i < 5; i++ {
+		limiter.Wait()
+	}
+	if elapsed := clock.Since(start); elapsed != 2*time.Second {
+		t.Errorf("Expected 2s, got %v", elapsed)
+	}
+
+	// Idle time isn't saved up
+	clock.Step(time.Minute)
+	before := clock.Now()
+	limiter.Wait()
+	limiter.Wait()
+	if elapsed := clock.Since(before); elapsed != 500*time.Millisecond {
+		t.Errorf("Expected 500ms, got %v", elapsed)
+	}
+}
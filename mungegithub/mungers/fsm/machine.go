@@ -0,0 +1,439 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/contrib/mungegithub/github"
+	"k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/matchers/event"
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+// Item is a single entry in the history of an issue: either an event, a
+// comment, or a review. Issue is the issue the item belongs to, if known.
+type Item struct {
+	Event   *githubapi.IssueEvent
+	Comment *githubapi.IssueComment
+	Review  *github.PullRequestReview
+	Issue   *githubapi.Issue
+}
+
+// Date returns the creation time of the item, or nil if unknown.
+func (i *Item) Date() *time.Time {
+	if i.Event != nil {
+		return i.Event.CreatedAt
+	}
+	if i.Comment != nil {
+		return i.Comment.CreatedAt
+	}
+	if i.Review != nil {
+		return i.Review.SubmittedAt
+	}
+	return nil
+}
+
+func (i *Item) time() time.Time {
+	if date := i.Date(); date != nil {
+		return *date
+	}
+	return time.Time{}
+}
+
+type byDate []*Item
+
+func (b byDate) Len() int           { return len(b) }
+func (b byDate) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byDate) Less(i, j int) bool { return b[i].time().Before(b[j].time()) }
+
+// Timeline merges events and comments into a single list sorted by
+// creation date.
+func Timeline(events []*githubapi.IssueEvent, comments []*githubapi.IssueComment) []*Item {
+	return TimelineWithReviews(events, comments, nil)
+}
+
+// TimelineWithReviews is like Timeline, but also includes the reviews of
+// a pull request.
+func TimelineWithReviews(events []*githubapi.IssueEvent, comments []*githubapi.IssueComment, reviews []*github.PullRequestReview) []*Item {
+	items := []*Item{}
+	for _, e := range events {
+		items = append(items, &Item{Event: e})
+	}
+	for _, c := range comments {
+		items = append(items, &Item{Comment: c})
+	}
+	for _, r := range reviews {
+		items = append(items, &Item{Review: r})
+	}
+	sort.Stable(byDate(items))
+	return items
+}
+
+// Guard decides if a transition can be taken for a given item.
+type Guard interface {
+	Match(item *Item) bool
+}
+
+// EventGuard is a Guard that only matches events.
+type EventGuard struct {
+	Matcher event.Matcher
+}
+
+// Match returns true if the item is an event matched by the matcher
+func (g EventGuard) Match(item *Item) bool {
+	return item.Event != nil && g.Matcher.Match(item.Event)
+}
+
+// CommentGuard is a Guard that only matches comments.
+type CommentGuard struct {
+	Matcher comment.Matcher
+}
+
+// Match returns true if the item is a comment matched by the matcher
+func (g CommentGuard) Match(item *Item) bool {
+	return item.Comment != nil && g.Matcher.Match(item.Comment)
+}
+
+// StateConfig describes one of the states of a Machine.
+type StateConfig struct {
+	Name string
+	// Parent is the composite state containing this state, if any.
+	Parent string
+	// Initial is the child entered when a composite state is entered.
+	Initial string
+	// Labels that the issue must have while it is in this state. They
+	// are removed when the issue moves to another state.
+	Labels []string
+	// Actions that must be performed while the issue is in this state.
+	Actions []Action
+	// OnEnter and OnExit hooks are run when a transition enters or
+	// leaves this state.
+	OnEnter []Hook
+	OnExit  []Hook
+	// Activity matches items that reset the timer of the timed
+	// transitions leaving this state.
+	Activity Guard
+}
+
+// Transition moves the machine from one state to another when the
+// Guard matches an item of the issue history. If After is set, the
+// transition is timed instead: it has no Guard and is taken when the
+// issue has been in the state, without activity, for that long.
+type Transition struct {
+	From  string
+	To    string
+	Guard Guard
+	After time.Duration
+	// Description is a short human readable explanation of the guard.
+	Description string
+}
+
+// Machine is a finite-state-machine that computes the state of an
+// issue by replaying its history.
+type Machine struct {
+	Initial     string
+	States      []StateConfig
+	Transitions []Transition
+
+	// Store, if set, is used by Munge to only evaluate the part of
+	// the history that is new since the last pass.
+	Store Store
+	// Clock is used to evaluate timed transitions. Defaults to the
+	// real clock.
+	Clock utilclock.Clock
+	// Metrics, if set, records the time spent in each state. It is
+	// only updated when Store is set, otherwise the same history would
+	// be counted on every pass.
+	Metrics *Metrics
+	// Limiter, if set, is waited on before each action. Munge is safe
+	// to call on several issues concurrently.
+	Limiter *RateLimiter
+	// Dashboard, if set, is updated with the state of each issue.
+	Dashboard *Dashboard
+}
+
+// Step is a transition that was taken while processing the history.
+// From and To are leaf states, while the transition may have been
+// defined on one of their ancestors. Item is nil for timed transitions.
+// Dwell is the time spent in From, if known.
+type Step struct {
+	Item       *Item
+	Transition *Transition
+	From       string
+	To         string
+	Time       time.Time
+	Dwell      time.Duration
+}
+
+// Result is the outcome of processing an issue with a Machine.
+type Result struct {
+	// Start is the state processing started from.
+	Start   string
+	State   string
+	Steps   []Step
+	Actions []Action
+	// Checkpoint can be stored to resume processing later.
+	Checkpoint Checkpoint
+}
+
+// GetState returns the configuration of the state with the given name.
+func (m *Machine) GetState(name string) (*StateConfig, bool) {
+	for i := range m.States {
+		if m.States[i].Name == name {
+			return &m.States[i], true
+		}
+	}
+	return nil, false
+}
+
+// transitionsFrom returns the transitions leaving the given state, in
+// the order they were declared, followed by the transitions leaving its
+// ancestors.
+func (m *Machine) transitionsFrom(state string) []*Transition {
+	out := []*Transition{}
+	for _, s := range m.ancestors(state) {
+		for i := range m.Transitions {
+			if m.Transitions[i].From == s {
+				out = append(out, &m.Transitions[i])
+			}
+		}
+	}
+	return out
+}
+
+func (m *Machine) check() error {
+	if _, ok := m.GetState(m.Initial); !ok {
+		return fmt.Errorf("unknown initial state %q", m.Initial)
+	}
+	for _, t := range m.Transitions {
+		if _, ok := m.GetState(t.From); !ok {
+			return fmt.Errorf("transition from unknown state %q", t.From)
+		}
+		if _, ok := m.GetState(t.To); !ok {
+			return fmt.Errorf("transition to unknown state %q", t.To)
+		}
+		if t.After < 0 {
+			return fmt.Errorf("transition from %q to %q has a negative delay", t.From, t.To)
+		}
+		if (t.Guard == nil) == (t.After == 0) {
+			return fmt.Errorf("transition from %q to %q must have either a guard or a delay", t.From, t.To)
+		}
+	}
+	return m.checkHierarchy()
+}
+
+// Process replays the events and comments of the issue through the
+// machine. It returns the state the issue ends up in, and the actions
+// required to bring the issue labels in line with that state.
+func (m *Machine) Process(issue *githubapi.Issue, events []*githubapi.IssueEvent, comments []*githubapi.IssueComment) (*Result, error) {
+	return m.Resume(nil, issue, events, comments)
+}
+
+// Resume is like Process, but starts from the given checkpoint and only
+// replays events and comments that are newer. A nil checkpoint, or one
+// whose state no longer exists in the machine, replays everything.
+func (m *Machine) Resume(checkpoint *Checkpoint, issue *githubapi.Issue, events []*githubapi.IssueEvent, comments []*githubapi.IssueComment) (*Result, error) {
+	return m.ResumeTimeline(checkpoint, issue, Timeline(events, comments))
+}
+
+// ResumeTimeline is like Resume, for a timeline that may include reviews.
+func (m *Machine) ResumeTimeline(checkpoint *Checkpoint, issue *githubapi.Issue, timeline []*Item) (*Result, error) {
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+
+	if checkpoint != nil {
+		if _, ok := m.GetState(checkpoint.State); !ok || m.isComposite(checkpoint.State) {
+			checkpoint = nil
+		}
+	}
+	if checkpoint == nil {
+		checkpoint = &Checkpoint{State: m.leaf(m.Initial)}
+		if issue.CreatedAt != nil {
+			checkpoint.EnteredAt = *issue.CreatedAt
+		}
+	}
+
+	result := &Result{Start: checkpoint.State, State: checkpoint.State, Checkpoint: *checkpoint}
+	for _, item := range timeline {
+		if !result.Checkpoint.isNew(item) {
+			continue
+		}
+		item.Issue = issue
+		result.Checkpoint.advance(item)
+		if result.Checkpoint.EnteredAt.IsZero() {
+			result.Checkpoint.EnteredAt = item.time()
+		}
+		m.takeTimed(result, item.time())
+		if m.isActivity(result.State, item) {
+			result.Checkpoint.LastActivity = item.time()
+		}
+		for _, t := range m.transitionsFrom(result.State) {
+			if t.Guard != nil && t.Guard.Match(item) {
+				m.take(result, t, item, item.time())
+				break
+			}
+		}
+	}
+	m.takeTimed(result, m.now())
+	result.Checkpoint.State = result.State
+	result.Actions = m.hookActions(result.Steps)
+	result.Actions = append(result.Actions, m.labelActions(issue, result.State)...)
+	ancestors := m.ancestors(result.State)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if s, ok := m.GetState(ancestors[i]); ok {
+			result.Actions = append(result.Actions, s.Actions...)
+		}
+	}
+	return result, nil
+}
+
+// take moves the machine along the transition.
+func (m *Machine) take(result *Result, t *Transition, item *Item, at time.Time) {
+	to := m.leaf(t.To)
+	step := Step{Item: item, Transition: t, From: result.State, To: to, Time: at}
+	if !result.Checkpoint.EnteredAt.IsZero() {
+		step.Dwell = at.Sub(result.Checkpoint.EnteredAt)
+	}
+	result.Steps = append(result.Steps, step)
+	result.State = to
+	result.Checkpoint.EnteredAt = at
+	result.Checkpoint.LastActivity = time.Time{}
+	result.Checkpoint.LastTransition = step.String()
+}
+
+// labelActions computes the label changes needed for the issue to
+// reflect the given state.
+func (m *Machine) labelActions(issue *githubapi.Issue, state string) []Action {
+	current := map[string]bool{}
+	for _, l := range issue.Labels {
+		if l.Name != nil {
+			current[*l.Name] = true
+		}
+	}
+
+	wanted := map[string]bool{}
+	actions := []Action{}
+	ancestors := m.ancestors(state)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		s, ok := m.GetState(ancestors[i])
+		if !ok {
+			continue
+		}
+		for _, l := range s.Labels {
+			if wanted[l] {
+				continue
+			}
+			wanted[l] = true
+			if !current[l] {
+				actions = append(actions, AddLabel(l))
+			}
+		}
+	}
+	for _, s := range m.States {
+		for _, l := range s.Labels {
+			if current[l] && !wanted[l] {
+				actions = append(actions, RemoveLabel(l))
+				// Don't remove twice if shared by two states
+				current[l] = false
+			}
+		}
+	}
+	return actions
+}
+
+// Munge computes the state of the object and applies the required
+// actions. It can be passed to ForEachIssueDo.
+func (m *Machine) Munge(obj *github.MungeObject) error {
+	events, err := obj.GetEvents()
+	if err != nil {
+		return err
+	}
+	comments, err := obj.ListComments()
+	if err != nil {
+		return err
+	}
+
+	reviews := []*github.PullRequestReview{}
+	if m.usesReviews() {
+		if reviews, err = obj.ListReviews(); err != nil {
+			return err
+		}
+	}
+
+	var checkpoint *Checkpoint
+	if m.Store != nil {
+		if checkpoint, err = m.Store.Get(obj.Number()); err != nil {
+			return err
+		}
+	}
+	result, err := m.ResumeTimeline(checkpoint, obj.Issue, TimelineWithReviews(events, comments, reviews))
+	if err != nil {
+		return err
+	}
+	for i, action := range result.Actions {
+		if m.Limiter != nil {
+			m.Limiter.Wait()
+		}
+		if err := action.Do(obj); err != nil {
+			if m.Dashboard != nil {
+				m.Dashboard.Update(obj.Issue, result, result.Actions[i:])
+			}
+			return err
+		}
+	}
+	if m.Dashboard != nil {
+		m.Dashboard.Update(obj.Issue, result, nil)
+	}
+	if m.Store == nil {
+		return nil
+	}
+	if m.Metrics != nil {
+		m.Metrics.Observe(obj.Issue, result.Steps)
+	}
+	return m.Store.Set(obj.Number(), &result.Checkpoint)
+}
+
+// machineState runs a Machine as a State, so that configured machines
+// and the built-in states are driven the same way by Run.
+type machineState struct {
+	machine *Machine
+}
+
+var _ State = &machineState{}
+
+// AsState returns a State that computes the state of the object with the
+// machine and applies the required actions, before ending.
+func (m *Machine) AsState() State {
+	return &machineState{machine: m}
+}
+
+// Process does the necessary processing to compute whether to stay in
+// this state, or proceed to the next.
+func (s *machineState) Process(obj *github.MungeObject) (State, error) {
+	return &End{}, s.machine.Munge(obj)
+}
+
+// Name is the name of the state machine's state.
+func (s *machineState) Name() string {
+	return "Machine"
+}
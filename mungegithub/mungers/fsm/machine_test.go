@@ -0,0 +1,195 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	"k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/matchers/event"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func labelEvent(name, label string, t int64) *githubapi.IssueEvent {
+	date := time.Unix(t, 0)
+	return &githubapi.IssueEvent{
+		Event:     &name,
+		Label:     &githubapi.Label{Name: &label},
+		CreatedAt: &date,
+	}
+}
+
+func testMachine() *Machine {
+	return &Machine{
+		Initial: "review",
+		States: []StateConfig{
+			{Name: "review", Labels: []string{"state/review"}},
+			{Name: "changes", Labels: []string{"state/changes"}},
+			{Name: "done"},
+		},
+		Transitions: []Transition{
+			{
+				From:  "review",
+				To:    "done",
+				Guard: EventGuard{event.And{event.AddLabel{}, event.LabelName("lgtm")}},
+			},
+			{
+				From:  "review",
+				To:    "changes",
+				Guard: CommentGuard{comment.CommandName("changes")},
+			},
+			{
+				From:  "changes",
+				To:    "review",
+				Guard: CommentGuard{comment.CommandName("ready")},
+			},
+		},
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	events := []*githubapi.IssueEvent{
+		labelEvent("labeled", "a", 10),
+		labelEvent("labeled", "b", 30),
+	}
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", time.Unix(20, 0), "body"),
+	}
+	items := Timeline(events, comments)
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+	if items[0].Event != events[0] || items[1].Comment != comments[0] || items[2].Event != events[1] {
+		t.Errorf("Items are not sorted by date: %v", items)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		events   []*githubapi.IssueEvent
+		comments []*githubapi.IssueComment
+		state    string
+		steps    int
+		actions  []Action
+	}{
+		{
+			name:    "No history stays in the initial state",
+			state:   "review",
+			actions: []Action{AddLabel("state/review")},
+		},
+		{
+			name:     "Command moves to changes",
+			labels:   []string{"state/review"},
+			comments: []*githubapi.IssueComment{github_test.Comment(1, "user", time.Unix(10, 0), "/changes")},
+			state:    "changes",
+			steps:    1,
+			actions:  []Action{AddLabel("state/changes"), RemoveLabel("state/review")},
+		},
+		{
+			name:   "Back and forth then lgtm",
+			labels: []string{"state/changes"},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "user", time.Unix(10, 0), "/changes"),
+				github_test.Comment(2, "user", time.Unix(20, 0), "/ready"),
+			},
+			events:  []*githubapi.IssueEvent{labelEvent("labeled", "lgtm", 30)},
+			state:   "done",
+			steps:   3,
+			actions: []Action{RemoveLabel("state/changes")},
+		},
+		{
+			name:   "Guard that doesn't leave current state is ignored",
+			labels: []string{"state/review"},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "user", time.Unix(10, 0), "/ready"),
+			},
+			state:   "review",
+			actions: []Action{},
+		},
+	}
+
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, test.labels, true)
+		result, err := testMachine().Process(issue, test.events, test.comments)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.State != test.state {
+			t.Errorf("%s: expected state %q, got %q", test.name, test.state, result.State)
+		}
+		if len(result.Steps) != test.steps {
+			t.Errorf("%s: expected %d steps, got %d", test.name, test.steps, len(result.Steps))
+		}
+		if !reflect.DeepEqual(result.Actions, test.actions) {
+			t.Errorf("%s: expected actions %v, got %v", test.name, test.actions, result.Actions)
+		}
+	}
+}
+
+func TestProcessInvalidMachine(t *testing.T) {
+	m := testMachine()
+	m.Transitions = append(m.Transitions, Transition{From: "done", To: "unknown", Guard: EventGuard{event.True{}}})
+	if _, err := m.Process(github_test.Issue("user", 1, nil, true), nil, nil); err == nil {
+		t.Error("Expected an error for transition to unknown state")
+	}
+}
+
+func TestRunMachineAsState(t *testing.T) {
+	issue := github_test.Issue("user", 1, nil, true)
+	client, server, mux := github_test.InitServer(t, issue, nil, nil, nil, nil, nil, nil)
+	defer server.Close()
+	empty := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("[]"))
+	}
+	mux.HandleFunc("/repos/o/r/issues/1/events", empty)
+	mux.HandleFunc("/repos/o/r/issues/1/comments", empty)
+	added := []string{}
+	mux.HandleFunc("/repos/o/r/issues/1/labels", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			labels := []string{}
+			json.NewDecoder(r.Body).Decode(&labels)
+			added = append(added, labels...)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("[]"))
+	})
+
+	config := &github_util.Config{Org: "o", Project: "r"}
+	config.SetClient(client)
+	obj, err := config.GetObject(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := Run(testMachine().AsState(), obj); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(added, []string{"state/review"}) {
+		t.Errorf("Expected the initial state label to be added, got %v", added)
+	}
+}
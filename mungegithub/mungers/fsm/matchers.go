@@ -0,0 +1,259 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/matchers/event"
+)
+
+// Besides the registered guards, a guard can be written as a comment or
+// event matcher from the matchers packages. For example:
+//
+//   guard:
+//     comment:
+//       and:
+//       - name: command
+//         args: [hold]
+//       - not: {name: author, args: [some-bot]}
+
+// CommentMatcherFactory creates a comment.Matcher from the arguments
+// given in the configuration file.
+type CommentMatcherFactory func(args []string) (comment.Matcher, error)
+
+// EventMatcherFactory creates an event.Matcher from the arguments given
+// in the configuration file.
+type EventMatcherFactory func(args []string) (event.Matcher, error)
+
+var (
+	commentMatcherFactories = map[string]CommentMatcherFactory{}
+	eventMatcherFactories   = map[string]EventMatcherFactory{}
+)
+
+// RegisterCommentMatcher makes a comment matcher available by name in
+// configuration files.
+func RegisterCommentMatcher(name string, factory CommentMatcherFactory) error {
+	if _, found := commentMatcherFactories[name]; found {
+		return fmt.Errorf("a comment matcher with that name (%s) already exists", name)
+	}
+	commentMatcherFactories[name] = factory
+	return nil
+}
+
+// RegisterEventMatcher makes an event matcher available by name in
+// configuration files.
+func RegisterEventMatcher(name string, factory EventMatcherFactory) error {
+	if _, found := eventMatcherFactories[name]; found {
+		return fmt.Errorf("an event matcher with that name (%s) already exists", name)
+	}
+	eventMatcherFactories[name] = factory
+	return nil
+}
+
+// matcherFile is either a registered matcher, or a combination of
+// matchers.
+type matcherFile struct {
+	Name string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Args []string      `json:"args,omitempty" yaml:"args,omitempty"`
+	And  []matcherFile `json:"and,omitempty" yaml:"and,omitempty"`
+	Or   []matcherFile `json:"or,omitempty" yaml:"or,omitempty"`
+	Not  *matcherFile  `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// String displays the matcher as name(args), and(...), or(...) or not(...)
+func (f matcherFile) String() string {
+	join := func(files []matcherFile) string {
+		strs := []string{}
+		for _, file := range files {
+			strs = append(strs, file.String())
+		}
+		return strings.Join(strs, ", ")
+	}
+	switch {
+	case f.And != nil:
+		return "and(" + join(f.And) + ")"
+	case f.Or != nil:
+		return "or(" + join(f.Or) + ")"
+	case f.Not != nil:
+		return "not(" + f.Not.String() + ")"
+	}
+	return reference{Name: f.Name, Args: f.Args}.String()
+}
+
+func (f matcherFile) check() error {
+	set := 0
+	if f.Name != "" {
+		set++
+	}
+	if f.And != nil {
+		set++
+	}
+	if f.Or != nil {
+		set++
+	}
+	if f.Not != nil {
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("matcher must have exactly one of name, and, or, not")
+	}
+	return nil
+}
+
+func readCommentMatcher(f matcherFile) (comment.Matcher, error) {
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+	readAll := func(files []matcherFile) ([]comment.Matcher, error) {
+		matchers := []comment.Matcher{}
+		for _, file := range files {
+			m, err := readCommentMatcher(file)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, m)
+		}
+		return matchers, nil
+	}
+	switch {
+	case f.And != nil:
+		matchers, err := readAll(f.And)
+		return comment.And(matchers), err
+	case f.Or != nil:
+		matchers, err := readAll(f.Or)
+		return comment.Or(matchers), err
+	case f.Not != nil:
+		m, err := readCommentMatcher(*f.Not)
+		return comment.Not{Matcher: m}, err
+	}
+	factory, ok := commentMatcherFactories[f.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown comment matcher %q", f.Name)
+	}
+	return factory(f.Args)
+}
+
+func readEventMatcher(f matcherFile) (event.Matcher, error) {
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+	readAll := func(files []matcherFile) ([]event.Matcher, error) {
+		matchers := []event.Matcher{}
+		for _, file := range files {
+			m, err := readEventMatcher(file)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, m)
+		}
+		return matchers, nil
+	}
+	switch {
+	case f.And != nil:
+		matchers, err := readAll(f.And)
+		return event.And(matchers), err
+	case f.Or != nil:
+		matchers, err := readAll(f.Or)
+		return event.Or(matchers), err
+	case f.Not != nil:
+		m, err := readEventMatcher(*f.Not)
+		return event.Not{Matcher: m}, err
+	}
+	factory, ok := eventMatcherFactories[f.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event matcher %q", f.Name)
+	}
+	return factory(f.Args)
+}
+
+func init() {
+	RegisterCommentMatcher("human", func(args []string) (comment.Matcher, error) {
+		return comment.HumanActor(), expectArgs("human", args, 0)
+	})
+	RegisterCommentMatcher("bot", func(args []string) (comment.Matcher, error) {
+		return comment.BotAuthor(), expectArgs("bot", args, 0)
+	})
+	RegisterCommentMatcher("author", func(args []string) (comment.Matcher, error) {
+		if err := expectArgs("author", args, 1); err != nil {
+			return nil, err
+		}
+		return comment.AuthorLogin(args[0]), nil
+	})
+	RegisterCommentMatcher("command", func(args []string) (comment.Matcher, error) {
+		if err := expectArgs("command", args, 1); err != nil {
+			return nil, err
+		}
+		return commandMatcher(args[0]), nil
+	})
+	RegisterCommentMatcher("command-name", func(args []string) (comment.Matcher, error) {
+		if err := expectArgs("command-name", args, 1); err != nil {
+			return nil, err
+		}
+		return comment.CommandName(args[0]), nil
+	})
+	RegisterCommentMatcher("command-arguments", func(args []string) (comment.Matcher, error) {
+		if err := expectArgs("command-arguments", args, 1); err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return comment.CommandArguments(*re), nil
+	})
+	RegisterCommentMatcher("notification", func(args []string) (comment.Matcher, error) {
+		if err := expectArgs("notification", args, 1); err != nil {
+			return nil, err
+		}
+		return comment.MungerNotificationName(args[0]), nil
+	})
+
+	RegisterEventMatcher("human", func(args []string) (event.Matcher, error) {
+		return event.HumanActor(), expectArgs("human", args, 0)
+	})
+	RegisterEventMatcher("bot", func(args []string) (event.Matcher, error) {
+		return event.BotActor(), expectArgs("bot", args, 0)
+	})
+	RegisterEventMatcher("actor", func(args []string) (event.Matcher, error) {
+		if err := expectArgs("actor", args, 1); err != nil {
+			return nil, err
+		}
+		return event.Actor(args[0]), nil
+	})
+	RegisterEventMatcher("labeled", func(args []string) (event.Matcher, error) {
+		return event.AddLabel{}, expectArgs("labeled", args, 0)
+	})
+	RegisterEventMatcher("unlabeled", func(args []string) (event.Matcher, error) {
+		return event.RemoveLabel{}, expectArgs("unlabeled", args, 0)
+	})
+	RegisterEventMatcher("label", func(args []string) (event.Matcher, error) {
+		if err := expectArgs("label", args, 1); err != nil {
+			return nil, err
+		}
+		return event.LabelName(args[0]), nil
+	})
+	RegisterEventMatcher("label-prefix", func(args []string) (event.Matcher, error) {
+		if err := expectArgs("label-prefix", args, 1); err != nil {
+			return nil, err
+		}
+		return event.LabelPrefix(args[0]), nil
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+const testMatcherConfig = `
+initial: open
+states: [{name: open}, {name: held}]
+transitions:
+- from: open
+  to: held
+  guard:
+    comment:
+      and:
+      - name: command
+        args: [hold]
+      - not: {name: author, args: [author]}
+- from: held
+  to: open
+  guard:
+    event:
+      and:
+      - name: unlabeled
+      - or:
+        - {name: label, args: [do-not-merge]}
+        - {name: label-prefix, args: [hold/]}
+`
+
+func TestMatcherGuards(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testMatcherConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := m.Transitions[0].Description; d != "comment(and(command(hold), not(author(author))))" {
+		t.Errorf("Unexpected description %q", d)
+	}
+
+	tests := []struct {
+		name     string
+		events   []*githubapi.IssueEvent
+		comments []*githubapi.IssueComment
+		state    string
+	}{
+		{
+			name: "Held by reviewer",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/hold"),
+			},
+			state: "held",
+		},
+		{
+			name: "Author can't hold",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "author", time.Unix(10, 0), "/hold"),
+			},
+			state: "open",
+		},
+		{
+			name: "Released by label prefix",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/hold"),
+			},
+			events: []*githubapi.IssueEvent{labelEvent("unlabeled", "hold/legal", 20)},
+			state:  "open",
+		},
+		{
+			name: "Other label",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/hold"),
+			},
+			events: []*githubapi.IssueEvent{labelEvent("unlabeled", "lgtm", 20)},
+			state:  "held",
+		},
+	}
+	for _, test := range tests {
+		result, err := m.Process(github_test.Issue("author", 1, nil, true), test.events, test.comments)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.State != test.state {
+			t.Errorf("%s: expected state %q, got %q", test.name, test.state, result.State)
+		}
+	}
+}
+
+func TestMatcherGuardErrors(t *testing.T) {
+	tests := []string{
+		"{comment: {name: unknown}}",
+		"{event: {name: command, args: [hold]}}",
+		"{comment: {name: author}}",
+		"{comment: {name: human, not: {name: bot}}}",
+		"{name: command, args: [hold], comment: {name: human}}",
+		"{comment: {name: human}, event: {name: human}}",
+	}
+	for _, guard := range tests {
+		config := "initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a, guard: " + guard + "}]"
+		if _, err := ReadMachine(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected an error for %s", guard)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"strings"
+
+	githubapi "github.com/google/go-github/github"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dwellBuckets go from one hour to about three months.
+var dwellBuckets = prometheus.ExponentialBuckets(3600, 2, 12)
+
+// Metrics exports, as prometheus histograms, how long issues stay in each
+// leaf state before leaving it. The time is also broken down by the
+// issue labels matching one of the prefixes (e.g. "sig/").
+type Metrics struct {
+	labelPrefixes []string
+	dwell         *prometheus.HistogramVec
+	dwellByLabel  *prometheus.HistogramVec
+}
+
+// NewMetrics creates the histograms. They still need to be registered
+// with prometheus.
+func NewMetrics(labelPrefixes []string) *Metrics {
+	return &Metrics{
+		labelPrefixes: labelPrefixes,
+		dwell: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fsm_state_dwell_seconds",
+			Help:    "Time spent by issues in a state before leaving it.",
+			Buckets: dwellBuckets,
+		}, []string{"state"}),
+		dwellByLabel: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "fsm_state_dwell_by_label_seconds",
+			Help:    "Time spent by issues in a state before leaving it, by issue label.",
+			Buckets: dwellBuckets,
+		}, []string{"state", "label"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.dwell.Describe(ch)
+	m.dwellByLabel.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.dwell.Collect(ch)
+	m.dwellByLabel.Collect(ch)
+}
+
+// Observe records the states left by the issue. Steps whose dwell time
+// is unknown are ignored.
+func (m *Metrics) Observe(issue *githubapi.Issue, steps []Step) {
+	labels := m.matchingLabels(issue)
+	for _, step := range steps {
+		if step.Dwell <= 0 {
+			continue
+		}
+		seconds := step.Dwell.Seconds()
+		m.dwell.WithLabelValues(step.From).Observe(seconds)
+		for _, label := range labels {
+			m.dwellByLabel.WithLabelValues(step.From, label).Observe(seconds)
+		}
+	}
+}
+
+func (m *Metrics) matchingLabels(issue *githubapi.Issue) []string {
+	labels := []string{}
+	for _, l := range issue.Labels {
+		if l.Name == nil {
+			continue
+		}
+		for _, prefix := range m.labelPrefixes {
+			if strings.HasPrefix(*l.Name, prefix) {
+				labels = append(labels, *l.Name)
+				break
+			}
+		}
+	}
+	return labels
+}
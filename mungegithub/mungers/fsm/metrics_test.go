@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func histogram(t *testing.T, vec *prometheus.HistogramVec, labels ...string) *dto.Histogram {
+	m := &dto.Metric{}
+	if err := vec.WithLabelValues(labels...).Write(m); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return m.Histogram
+}
+
+func TestMetrics(t *testing.T) {
+	m := testMachine()
+	issue := github_test.Issue("user", 1, []string{"sig/node", "kind/bug", "sig/apps"}, true)
+	start := time.Unix(0, 0)
+	issue.CreatedAt = &start
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", start.Add(2*time.Hour), "/changes"),
+		github_test.Comment(2, "user", start.Add(5*time.Hour), "/ready"),
+	}
+	result, err := m.Process(issue, nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	metrics := NewMetrics([]string{"sig/"})
+	metrics.Observe(issue, result.Steps)
+
+	tests := []struct {
+		vec    *prometheus.HistogramVec
+		labels []string
+		count  uint64
+		sum    float64
+	}{
+		{metrics.dwell, []string{"review"}, 1, 2 * 3600},
+		{metrics.dwell, []string{"changes"}, 1, 3 * 3600},
+		{metrics.dwellByLabel, []string{"review", "sig/node"}, 1, 2 * 3600},
+		{metrics.dwellByLabel, []string{"changes", "sig/apps"}, 1, 3 * 3600},
+		{metrics.dwellByLabel, []string{"review", "kind/bug"}, 0, 0},
+	}
+	for _, test := range tests {
+		h := histogram(t, test.vec, test.labels...)
+		if h.GetSampleCount() != test.count || h.GetSampleSum() != test.sum {
+			t.Errorf("%v: expected %d samples summing to %v, got %d and %v",
+				test.labels, test.count, test.sum, h.GetSampleCount(), h.GetSampleSum())
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// reviewPreset models the Kubernetes review flow:
+// needs-review -> changes-requested -> approved -> lgtm -> merge-queue.
+// It is also meant to be a reference when writing custom configurations.
+const reviewPreset = `
+initial: needs-review
+states:
+# A reviewer must look at the PR.
+- name: needs-review
+  labels: [state/needs-review]
+# The reviewer asked for changes, the ball is in the author's court. Only
+# the author can send the PR back for review, unless a reviewer approves
+# the PR directly.
+- name: changes-requested
+  labels: [state/changes-requested]
+  onEnter:
+  - name: notify
+    args: [CHANGES-REQUESTED, "", "Please address the comments and type /ready-for-review when done."]
+# An approver approved the change.
+- name: approved
+  labels: [state/approved]
+# An assignee said /lgtm, waiting for the lgtm label to be applied.
+- name: lgtm
+  labels: [state/lgtm]
+# The PR has both lgtm and approved labels and waits in the submit queue.
+- name: merge-queue
+  labels: [state/merge-queue]
+transitions:
+- from: needs-review
+  to: changes-requested
+  guard: {name: command, args: [request-changes]}
+- from: needs-review
+  to: changes-requested
+  guard: {name: review, args: [CHANGES_REQUESTED]}
+- from: changes-requested
+  to: needs-review
+  guard: {name: command-by, args: [ready-for-review, author]}
+- from: changes-requested
+  to: approved
+  guard: {name: review, args: [APPROVED]}
+- from: changes-requested
+  to: approved
+  guard: {name: label-added, args: [approved]}
+- from: needs-review
+  to: approved
+  guard: {name: review, args: [APPROVED]}
+- from: needs-review
+  to: approved
+  guard: {name: label-added, args: [approved]}
+- from: approved
+  to: changes-requested
+  guard: {name: command, args: [request-changes]}
+- from: approved
+  to: changes-requested
+  guard: {name: review, args: [CHANGES_REQUESTED]}
+- from: approved
+  to: needs-review
+  guard: {name: label-removed, args: [approved]}
+- from: approved
+  to: lgtm
+  guard: {name: command-by, args: [lgtm, assignee]}
+- from: approved
+  to: merge-queue
+  guard: {name: label-added, args: [lgtm]}
+- from: lgtm
+  to: merge-queue
+  guard: {name: label-added, args: [lgtm]}
+- from: lgtm
+  to: approved
+  guard: {name: command-by, args: [lgtm cancel, assignee]}
+- from: merge-queue
+  to: approved
+  guard: {name: label-removed, args: [lgtm]}
+- from: merge-queue
+  to: needs-review
+  guard: {name: label-removed, args: [approved]}
+`
+
+var presets = map[string]string{
+	"review": reviewPreset,
+}
+
+// Presets returns the names of the built-in machines.
+func Presets() []string {
+	names := []string{}
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadPreset returns the built-in machine with the given name.
+func LoadPreset(name string) (*Machine, error) {
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, must be one of %v", name, Presets())
+	}
+	return ReadMachine(strings.NewReader(preset))
+}
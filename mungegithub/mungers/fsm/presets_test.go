@@ -0,0 +1,158 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestReviewPreset(t *testing.T) {
+	m, err := LoadPreset("review")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reviewer := "reviewer"
+	tests := []struct {
+		name     string
+		events   []*githubapi.IssueEvent
+		comments []*githubapi.IssueComment
+		reviews  []*github.PullRequestReview
+		state    string
+	}{
+		{
+			name:  "New PR needs review",
+			state: "needs-review",
+		},
+		{
+			name: "Changes requested",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/request-changes"),
+			},
+			state: "changes-requested",
+		},
+		{
+			name: "Ready for review again",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/request-changes"),
+				github_test.Comment(2, "author", time.Unix(20, 0), "/ready-for-review"),
+			},
+			state: "needs-review",
+		},
+		{
+			name: "Only the author can ask for review again",
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/request-changes"),
+				github_test.Comment(2, "reviewer", time.Unix(20, 0), "/ready-for-review"),
+			},
+			state: "changes-requested",
+		},
+		{
+			name: "Approved after changes were requested",
+			reviews: []*github.PullRequestReview{
+				review(1, "reviewer", github.ReviewChangesRequested, 10),
+				review(2, "reviewer", github.ReviewApproved, 20),
+			},
+			state: "approved",
+		},
+		{
+			name:   "Approved label after changes were requested",
+			events: []*githubapi.IssueEvent{labelEvent("labeled", "approved", 20)},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/request-changes"),
+			},
+			state: "approved",
+		},
+		{
+			name:   "Approved",
+			events: []*githubapi.IssueEvent{labelEvent("labeled", "approved", 10)},
+			state:  "approved",
+		},
+		{
+			name:   "Approved then lgtm",
+			events: []*githubapi.IssueEvent{labelEvent("labeled", "approved", 10)},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(20, 0), "/lgtm"),
+			},
+			state: "lgtm",
+		},
+		{
+			name:   "Only assignees can lgtm",
+			events: []*githubapi.IssueEvent{labelEvent("labeled", "approved", 10)},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "someone", time.Unix(20, 0), "/lgtm"),
+			},
+			state: "approved",
+		},
+		{
+			name: "Lgtm cancelled",
+			events: []*githubapi.IssueEvent{
+				labelEvent("labeled", "approved", 10),
+			},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(20, 0), "/lgtm"),
+				github_test.Comment(2, "reviewer", time.Unix(30, 0), "/lgtm cancel"),
+			},
+			state: "approved",
+		},
+		{
+			name: "In the merge queue",
+			events: []*githubapi.IssueEvent{
+				labelEvent("labeled", "approved", 10),
+				labelEvent("labeled", "lgtm", 30),
+			},
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(20, 0), "/lgtm"),
+			},
+			state: "merge-queue",
+		},
+		{
+			name: "Out of the merge queue",
+			events: []*githubapi.IssueEvent{
+				labelEvent("labeled", "approved", 10),
+				labelEvent("labeled", "lgtm", 20),
+				labelEvent("unlabeled", "lgtm", 30),
+			},
+			state: "approved",
+		},
+	}
+
+	for _, test := range tests {
+		issue := github_test.Issue("author", 1, nil, true)
+		issue.Assignees = []*githubapi.User{{Login: &reviewer}}
+		result, err := m.ResumeTimeline(nil, issue, TimelineWithReviews(test.events, test.comments, test.reviews))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.State != test.state {
+			t.Errorf("%s: expected state %q, got %q", test.name, test.state, result.State)
+		}
+	}
+}
+
+func TestUnknownPreset(t *testing.T) {
+	if _, err := LoadPreset("unknown"); err == nil {
+		t.Error("Expected an error for unknown preset")
+	}
+}
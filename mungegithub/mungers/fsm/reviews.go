@@ -0,0 +1,79 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/github"
+)
+
+// ReviewGuard matches a pull request review in the given State, such as
+// github.ReviewApproved or github.ReviewChangesRequested. If Allowed is
+// not empty, the reviewer must be one of the listed logins, or have one
+// of the roles (AuthorRole, AssigneeRole) on the issue.
+type ReviewGuard struct {
+	State   string
+	Allowed []string
+}
+
+// Match returns true if the item is a review in the state, by an allowed
+// reviewer
+func (r ReviewGuard) Match(item *Item) bool {
+	review := item.Review
+	if review == nil || review.State == nil || !strings.EqualFold(*review.State, r.State) {
+		return false
+	}
+	if len(r.Allowed) == 0 {
+		return true
+	}
+	if review.User == nil || review.User.Login == nil {
+		return false
+	}
+	return isAllowed(item.Issue, *review.User.Login, r.Allowed)
+}
+
+// usesReviews returns true if some guard needs the reviews of the PR, so
+// that they are only fetched when needed.
+func (m *Machine) usesReviews() bool {
+	for _, t := range m.Transitions {
+		if _, ok := t.Guard.(ReviewGuard); ok {
+			return true
+		}
+	}
+	for _, s := range m.States {
+		if _, ok := s.Activity.(ReviewGuard); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	RegisterGuard("review", func(args []string) (Guard, error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("review expects a state and optionally allowed reviewers, got %d arguments", len(args))
+		}
+		switch strings.ToUpper(args[0]) {
+		case github.ReviewApproved, github.ReviewChangesRequested, github.ReviewCommented:
+		default:
+			return nil, fmt.Errorf("unknown review state %q", args[0])
+		}
+		return ReviewGuard{State: args[0], Allowed: args[1:]}, nil
+	})
+}
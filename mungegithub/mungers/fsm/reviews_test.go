@@ -0,0 +1,109 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func review(id int, login, state string, t int64) *github.PullRequestReview {
+	date := time.Unix(t, 0)
+	return &github.PullRequestReview{
+		ID:          &id,
+		User:        &githubapi.User{Login: &login},
+		State:       &state,
+		SubmittedAt: &date,
+	}
+}
+
+func TestReviewGuard(t *testing.T) {
+	issue := github_test.Issue("author", 1, nil, true)
+	tests := []struct {
+		name    string
+		guard   ReviewGuard
+		item    *Item
+		matches bool
+	}{
+		{"Approved", ReviewGuard{State: "approved"}, &Item{Review: review(1, "user", "APPROVED", 10)}, true},
+		{"Other state", ReviewGuard{State: "approved"}, &Item{Review: review(1, "user", "COMMENTED", 10)}, false},
+		{"Not a review", ReviewGuard{State: "approved"}, &Item{Comment: github_test.Comment(1, "user", time.Unix(10, 0), "APPROVED")}, false},
+		{"Allowed", ReviewGuard{State: "approved", Allowed: []string{"user"}}, &Item{Review: review(1, "user", "APPROVED", 10)}, true},
+		{"Not allowed", ReviewGuard{State: "approved", Allowed: []string{"other"}}, &Item{Review: review(1, "user", "APPROVED", 10)}, false},
+		{"Author", ReviewGuard{State: "commented", Allowed: []string{AuthorRole}}, &Item{Review: review(1, "author", "COMMENTED", 10)}, true},
+	}
+	for _, test := range tests {
+		test.item.Issue = issue
+		if matches := test.guard.Match(test.item); matches != test.matches {
+			t.Errorf("%s: expected match %v, got %v", test.name, test.matches, matches)
+		}
+	}
+
+	if _, err := ReadMachine(strings.NewReader("initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a, guard: {name: review, args: [LGTM]}}]")); err == nil {
+		t.Error("Expected an error for an unknown review state")
+	}
+}
+
+func TestReviewTransitions(t *testing.T) {
+	m, err := LoadPreset("review")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !m.usesReviews() {
+		t.Error("Expected the review preset to use reviews")
+	}
+	if testMachine().usesReviews() {
+		t.Error("Expected the test machine to not use reviews")
+	}
+
+	issue := github_test.Issue("author", 1, nil, true)
+	reviews := []*github.PullRequestReview{
+		review(1, "reviewer", "COMMENTED", 10),
+		review(2, "reviewer", "CHANGES_REQUESTED", 20),
+	}
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "author", time.Unix(30, 0), "/ready-for-review"),
+	}
+	result, err := m.ResumeTimeline(nil, issue, TimelineWithReviews(nil, comments, reviews))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "needs-review" || len(result.Steps) != 2 {
+		t.Fatalf("Expected needs-review in 2 steps, got %q in %d", result.State, len(result.Steps))
+	}
+	if s := result.Steps[0].String(); s != "needs-review -> changes-requested  on review CHANGES_REQUESTED by reviewer (guard review(CHANGES_REQUESTED))" {
+		t.Errorf("Unexpected step %q", s)
+	}
+	if result.Checkpoint.LastReviewID != 2 || result.Checkpoint.LastCommentID != 1 {
+		t.Errorf("Unexpected checkpoint %+v", result.Checkpoint)
+	}
+
+	// The review is not replayed
+	result, err = m.ResumeTimeline(&result.Checkpoint, issue, TimelineWithReviews(nil, comments, reviews))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "needs-review" || len(result.Steps) != 0 {
+		t.Errorf("Expected no step, got %q in %d", result.State, len(result.Steps))
+	}
+}
@@ -34,7 +34,13 @@ func ComputeState(obj *github.MungeObject) error {
 	}
 
 	// Every PR starts in the pre-review state.
-	var currentState State = &PreReview{}
+	return Run(&PreReview{}, obj)
+}
+
+// Run processes the object from the `start` state until it reaches the
+// end state.
+func Run(start State, obj *github.MungeObject) error {
+	currentState := start
 	for currentState.Name() != endState {
 		var err error
 		currentState, err = currentState.Process(obj)
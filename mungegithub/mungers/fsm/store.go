@@ -0,0 +1,159 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the state of an issue after it was last evaluated,
+// along with the last event and comment that were processed.
+type Checkpoint struct {
+	State         string `json:"state"`
+	LastEventID   int    `json:"lastEventID"`
+	LastCommentID int    `json:"lastCommentID"`
+	LastReviewID  int    `json:"lastReviewID,omitempty"`
+	// EnteredAt is when the issue entered the state, and LastActivity
+	// the last activity in that state. They drive timed transitions.
+	EnteredAt    time.Time `json:"enteredAt"`
+	LastActivity time.Time `json:"lastActivity"`
+	// LastTransition describes why the issue entered the state.
+	LastTransition string `json:"lastTransition,omitempty"`
+}
+
+// isNew returns true if the item comes after the checkpoint.
+func (c *Checkpoint) isNew(item *Item) bool {
+	if item.Event != nil && item.Event.ID != nil {
+		return *item.Event.ID > c.LastEventID
+	}
+	if item.Comment != nil && item.Comment.ID != nil {
+		return *item.Comment.ID > c.LastCommentID
+	}
+	if item.Review != nil && item.Review.ID != nil {
+		return *item.Review.ID > c.LastReviewID
+	}
+	return true
+}
+
+// advance moves the checkpoint past the item.
+func (c *Checkpoint) advance(item *Item) {
+	if item.Event != nil && item.Event.ID != nil && *item.Event.ID > c.LastEventID {
+		c.LastEventID = *item.Event.ID
+	}
+	if item.Comment != nil && item.Comment.ID != nil && *item.Comment.ID > c.LastCommentID {
+		c.LastCommentID = *item.Comment.ID
+	}
+	if item.Review != nil && item.Review.ID != nil && *item.Review.ID > c.LastReviewID {
+		c.LastReviewID = *item.Review.ID
+	}
+}
+
+// Store persists checkpoints so that the history of an issue doesn't
+// have to be replayed entirely on every pass.
+type Store interface {
+	// Get returns the checkpoint for the issue, or nil if there is none.
+	Get(issue int) (*Checkpoint, error)
+	Set(issue int, checkpoint *Checkpoint) error
+}
+
+// MemoryStore keeps checkpoints in memory.
+type MemoryStore struct {
+	lock        sync.Mutex
+	checkpoints map[int]Checkpoint
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: map[int]Checkpoint{}}
+}
+
+// Get returns the checkpoint for the issue, or nil if there is none.
+func (s *MemoryStore) Get(issue int) (*Checkpoint, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	c, ok := s.checkpoints[issue]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+// Set saves the checkpoint for the issue.
+func (s *MemoryStore) Set(issue int, checkpoint *Checkpoint) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.checkpoints[issue] = *checkpoint
+	return nil
+}
+
+// FileStore keeps checkpoints in memory, and writes them to a JSON file
+// when Save is called.
+type FileStore struct {
+	*MemoryStore
+	path string
+}
+
+// NewFileStore creates a FileStore backed by the given file. Existing
+// checkpoints are loaded if the file exists.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{MemoryStore: NewMemoryStore(), path: path}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.checkpoints); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes all the checkpoints to the file.
+func (s *FileStore) Save() error {
+	s.lock.Lock()
+	data, err := json.Marshal(s.checkpoints)
+	s.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic writes to a temporary file first, and then renames it
+// so that we never leave a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fsm")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c, _ := store.Get(1); c != nil {
+		t.Errorf("Expected no checkpoint, got %v", c)
+	}
+	checkpoint := &Checkpoint{State: "review", LastEventID: 3, LastCommentID: 5}
+	store.Set(1, checkpoint)
+	if err := store.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	store, err = NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c, err := store.Get(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c, checkpoint) {
+		t.Errorf("Expected %v, got %v", checkpoint, c)
+	}
+}
+
+func TestResume(t *testing.T) {
+	m := testMachine()
+	issue := github_test.Issue("user", 1, nil, true)
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", time.Unix(10, 0), "/changes"),
+		github_test.Comment(2, "user", time.Unix(20, 0), "/ready"),
+	}
+
+	result, err := m.Resume(&Checkpoint{State: "changes", LastCommentID: 1}, issue, nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "review" || len(result.Steps) != 1 {
+		t.Errorf("Expected one step to review, got %q and %d steps", result.State, len(result.Steps))
+	}
+	expected := Checkpoint{
+		State:          "review",
+		LastCommentID:  2,
+		EnteredAt:      time.Unix(20, 0),
+		LastTransition: `changes -> review  on comment by user: "/ready"`,
+	}
+	if result.Checkpoint != expected {
+		t.Errorf("Expected checkpoint %v, got %v", expected, result.Checkpoint)
+	}
+
+	// Nothing new since the checkpoint
+	result, err = m.Resume(&expected, issue, nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "review" || len(result.Steps) != 0 {
+		t.Errorf("Expected no step, got %q and %d steps", result.State, len(result.Steps))
+	}
+
+	// Unknown state in the checkpoint replays everything
+	result, err = m.Resume(&Checkpoint{State: "removed", LastCommentID: 2}, issue, nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "review" || len(result.Steps) != 2 {
+		t.Errorf("Expected full replay, got %q and %d steps", result.State, len(result.Steps))
+	}
+}
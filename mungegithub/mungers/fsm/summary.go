@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+// Summary counts how many issues are in each state, in total and for
+// each issue label matching one of the prefixes.
+type Summary struct {
+	labelPrefixes []string
+	// States lists the leaf states, in the order of the machine.
+	States []string `json:"states"`
+	// Total is the number of issues in each state.
+	Total map[string]int `json:"total"`
+	// ByLabel is the number of issues in each state, for each label.
+	ByLabel map[string]map[string]int `json:"byLabel,omitempty"`
+}
+
+// NewSummary creates an empty summary for the machine.
+func NewSummary(m *Machine, labelPrefixes []string) *Summary {
+	s := &Summary{
+		labelPrefixes: labelPrefixes,
+		States:        []string{},
+		Total:         map[string]int{},
+		ByLabel:       map[string]map[string]int{},
+	}
+	for _, state := range m.States {
+		if !m.isComposite(state.Name) {
+			s.States = append(s.States, state.Name)
+		}
+	}
+	return s
+}
+
+// Add counts the issue in the given state.
+func (s *Summary) Add(issue *githubapi.Issue, state string) {
+	s.Total[state]++
+	for _, l := range issue.Labels {
+		if l.Name == nil || !hasAnyPrefix(*l.Name, s.labelPrefixes) {
+			continue
+		}
+		if s.ByLabel[*l.Name] == nil {
+			s.ByLabel[*l.Name] = map[string]int{}
+		}
+		s.ByLabel[*l.Name][state]++
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the summary as JSON
+func (s *Summary) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// WriteTable writes the summary as a table with one row per state, and
+// one column per label.
+func (s *Summary) WriteTable(w io.Writer) error {
+	labels := []string{}
+	for label := range s.ByLabel {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "STATE\tTOTAL")
+	for _, label := range labels {
+		fmt.Fprintf(tw, "\t%s", label)
+	}
+	fmt.Fprintln(tw)
+	for _, state := range s.States {
+		fmt.Fprintf(tw, "%s\t%d", state, s.Total[state])
+		for _, label := range labels {
+			fmt.Fprintf(tw, "\t%d", s.ByLabel[label][state])
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
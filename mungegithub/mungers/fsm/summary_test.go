@@ -0,0 +1,59 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+)
+
+func TestSummary(t *testing.T) {
+	s := NewSummary(testMachine(), []string{"sig/"})
+	s.Add(github_test.Issue("user", 1, []string{"sig/node"}, true), "review")
+	s.Add(github_test.Issue("user", 2, []string{"sig/node", "sig/apps", "kind/bug"}, true), "review")
+	s.Add(github_test.Issue("user", 3, []string{"sig/apps"}, true), "changes")
+
+	buf := new(bytes.Buffer)
+	if err := s.WriteTable(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `STATE    TOTAL  sig/apps  sig/node
+review   2      1         2
+changes  1      1         0
+done     0      0         0
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := s.WriteJSON(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoded := &Summary{}
+	if err := json.Unmarshal(buf.Bytes(), decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoded.labelPrefixes = s.labelPrefixes
+	if !reflect.DeepEqual(decoded, s) {
+		t.Errorf("Expected %+v, got %+v", s, decoded)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing runs scripted scenarios through state machines, so that
+// workflows can be tested without writing histories by hand.
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	github_util "k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	"k8s.io/contrib/mungegithub/mungers/fsm"
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+
+	"github.com/google/go-github/github"
+)
+
+const (
+	// DefaultAuthor is the author of the issue if the scenario doesn't
+	// set one.
+	DefaultAuthor = "author"
+	// DefaultActor posts comments and changes labels if the step doesn't
+	// say who does.
+	DefaultActor = "reviewer"
+)
+
+// Step is one thing happening to the issue: a comment, a review (in a
+// state such as "APPROVED"), a label being added or removed, or time passing, followed by the state expected once
+// it has happened. Leave State empty to not check it.
+type Step struct {
+	// By is who does the step, DefaultActor if empty.
+	By      string
+	Comment string
+	Review  string
+	Label   string
+	Unlabel string
+	// Wait is the time passing before the step. A step can only wait,
+	// to trigger timed transitions.
+	Wait  time.Duration
+	State string
+}
+
+// String describes the step
+func (s Step) String() string {
+	by := s.By
+	if by == "" {
+		by = DefaultActor
+	}
+	str := ""
+	if s.Wait != 0 {
+		str = fmt.Sprintf("wait %v", s.Wait)
+	}
+	action := ""
+	switch {
+	case s.Comment != "":
+		action = fmt.Sprintf("%s says %q", by, s.Comment)
+	case s.Review != "":
+		action = fmt.Sprintf("%s reviews %s", by, s.Review)
+	case s.Label != "":
+		action = fmt.Sprintf("%s adds %s", by, s.Label)
+	case s.Unlabel != "":
+		action = fmt.Sprintf("%s removes %s", by, s.Unlabel)
+	}
+	if str != "" && action != "" {
+		return str + ", then " + action
+	}
+	return str + action
+}
+
+// Scenario is a scripted history for an issue.
+type Scenario struct {
+	Name string
+	// Author of the issue, DefaultAuthor if empty.
+	Author string
+	// Labels the issue has before the first step.
+	Labels []string
+	// Assignees of the issue.
+	Assignees []string
+	// State expected before the first step, if not empty.
+	Initial string
+	Steps   []Step
+}
+
+// Run replays the scenario through a copy of the machine, and returns
+// the state after each step. Items are one minute apart, plus the Wait
+// of each step.
+func Run(machine *fsm.Machine, scenario Scenario) ([]string, error) {
+	author := scenario.Author
+	if author == "" {
+		author = DefaultAuthor
+	}
+	now := time.Unix(0, 0).UTC()
+	issue := github_test.Issue(author, 1, scenario.Labels, true)
+	created := now
+	issue.CreatedAt = &created
+	for i := range scenario.Assignees {
+		issue.Assignees = append(issue.Assignees, &github.User{Login: &scenario.Assignees[i]})
+	}
+	clock := utilclock.NewFakeClock(now)
+	m := *machine
+	m.Clock = clock
+
+	events := []*github.IssueEvent{}
+	comments := []*github.IssueComment{}
+	reviews := []*github_util.PullRequestReview{}
+	states := []string{}
+	for i, step := range scenario.Steps {
+		now = now.Add(time.Minute + step.Wait)
+		clock.SetTime(now)
+		by := step.By
+		if by == "" {
+			by = DefaultActor
+		}
+		switch {
+		case step.Comment != "":
+			comments = append(comments, github_test.Comment(len(comments)+1, by, now, step.Comment))
+		case step.Review != "":
+			reviews = append(reviews, review(len(reviews)+1, by, step.Review, now))
+		case step.Label != "":
+			events = append(events, labelEvent(len(events)+1, "labeled", step.Label, by, now))
+			issue.Labels = append(issue.Labels, github.Label{Name: &step.Label})
+		case step.Unlabel != "":
+			events = append(events, labelEvent(len(events)+1, "unlabeled", step.Unlabel, by, now))
+			issue.Labels = removeLabel(issue.Labels, step.Unlabel)
+		case step.Wait == 0:
+			return nil, fmt.Errorf("step %d does nothing", i+1)
+		}
+
+		result, err := m.ResumeTimeline(nil, issue, fsm.TimelineWithReviews(events, comments, reviews))
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %v", i+1, step, err)
+		}
+		states = append(states, result.State)
+	}
+	return states, nil
+}
+
+// Check runs the scenarios and reports, for each of them, the steps that
+// didn't end up in the expected state.
+func Check(t *testing.T, machine *fsm.Machine, scenarios ...Scenario) {
+	for _, scenario := range scenarios {
+		if err := Diff(machine, scenario); err != nil {
+			t.Errorf("%v", err)
+		}
+	}
+}
+
+// Diff runs the scenario and returns an error showing the expected and
+// actual trajectories if they differ.
+func Diff(machine *fsm.Machine, scenario Scenario) error {
+	if scenario.Initial != "" {
+		if result, err := machine.Process(github_test.Issue(DefaultAuthor, 1, scenario.Labels, true), nil, nil); err != nil {
+			return fmt.Errorf("scenario %q: %v", scenario.Name, err)
+		} else if result.State != scenario.Initial {
+			return fmt.Errorf("scenario %q: expected initial state %q, got %q", scenario.Name, scenario.Initial, result.State)
+		}
+	}
+	states, err := Run(machine, scenario)
+	if err != nil {
+		return fmt.Errorf("scenario %q: %v", scenario.Name, err)
+	}
+
+	buf := new(bytes.Buffer)
+	failed := false
+	for i, step := range scenario.Steps {
+		mark := " "
+		if step.State != "" && step.State != states[i] {
+			mark = "!"
+			failed = true
+		}
+		expected := step.State
+		if expected == "" {
+			expected = "*"
+		}
+		fmt.Fprintf(buf, "%s %2d. %-40s expected %-20s got %s\n", mark, i+1, step, expected, states[i])
+	}
+	if failed {
+		return fmt.Errorf("scenario %q: unexpected trajectory:\n%s", scenario.Name, buf.String())
+	}
+	return nil
+}
+
+func review(id int, login, state string, date time.Time) *github_util.PullRequestReview {
+	return &github_util.PullRequestReview{
+		ID:          &id,
+		User:        &github.User{Login: &login},
+		State:       &state,
+		SubmittedAt: &date,
+	}
+}
+
+func labelEvent(id int, name, label, actor string, date time.Time) *github.IssueEvent {
+	return &github.IssueEvent{
+		ID:        &id,
+		Event:     &name,
+		Label:     &github.Label{Name: &label},
+		Actor:     &github.User{Login: &actor},
+		CreatedAt: &date,
+	}
+}
+
+func removeLabel(labels []github.Label, name string) []github.Label {
+	out := []github.Label{}
+	for _, l := range labels {
+		if l.Name == nil || *l.Name != name {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/contrib/mungegithub/mungers/fsm"
+)
+
+const timedConfig = `
+initial: open
+states:
+- name: open
+- name: stale
+transitions:
+- from: open
+  to: stale
+  after: 24h
+- from: stale
+  to: open
+  guard: {name: human-comment}
+`
+
+func TestReviewScenarios(t *testing.T) {
+	m, err := fsm.LoadPreset("review")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	Check(t, m,
+		Scenario{
+			Name:      "Happy path",
+			Initial:   "needs-review",
+			Assignees: []string{"reviewer"},
+			Steps: []Step{
+				{By: "approver", Label: "approved", State: "approved"},
+				{By: "someone", Comment: "/lgtm", State: "approved"},
+				{Comment: "/lgtm", State: "lgtm"},
+				{By: "k8s-merge-robot", Label: "lgtm", State: "merge-queue"},
+			},
+		},
+		Scenario{
+			Name: "Changes requested",
+			Steps: []Step{
+				{Comment: "/request-changes", State: "changes-requested"},
+				{Comment: "/ready-for-review", State: "changes-requested"},
+				{By: "author", Comment: "/ready-for-review", State: "needs-review"},
+				{Review: "CHANGES_REQUESTED", State: "changes-requested"},
+			},
+		},
+	)
+}
+
+func TestTimedScenario(t *testing.T) {
+	m, err := fsm.ReadMachine(strings.NewReader(timedConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	Check(t, m, Scenario{
+		Name: "Stale and back",
+		Steps: []Step{
+			{Wait: 23 * time.Hour, State: "open"},
+			{Wait: time.Hour, State: "stale"},
+			{Comment: "ping", State: "open"},
+		},
+	})
+}
+
+func TestDiff(t *testing.T) {
+	m, err := fsm.LoadPreset("review")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = Diff(m, Scenario{
+		Name: "Wrong",
+		Steps: []Step{
+			{Comment: "/request-changes"},
+			{Comment: "/lgtm", State: "lgtm"},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, expected := range []string{
+		`   1. reviewer says "/request-changes"`,
+		`!  2. reviewer says "/lgtm"`,
+		"got changes-requested",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in:\n%v", expected, err)
+		}
+	}
+
+	if _, err := Run(m, Scenario{Steps: []Step{{State: "lgtm"}}}); err == nil {
+		t.Error("Expected an error for an empty step")
+	}
+}
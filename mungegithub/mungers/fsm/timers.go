@@ -0,0 +1,82 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"time"
+
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+)
+
+// Timed transitions fire once the issue has been idle in its current
+// leaf state for the transition's delay. The timer starts when the
+// state is entered, and is reset by items matching the Activity guard of
+// the state or of one of its ancestors. Time spent in a sibling state
+// doesn't count towards a transition defined on a composite state.
+
+func (m *Machine) now() time.Time {
+	if m.Clock == nil {
+		return utilclock.RealClock{}.Now()
+	}
+	return m.Clock.Now()
+}
+
+// isActivity returns true if the item resets the timers of the state.
+func (m *Machine) isActivity(state string, item *Item) bool {
+	for _, name := range m.ancestors(state) {
+		if s, ok := m.GetState(name); ok && s.Activity != nil && s.Activity.Match(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextTimed returns the timed transition that fires first from the
+// current state, and when it fires.
+func (m *Machine) nextTimed(result *Result) (*Transition, time.Time) {
+	since := result.Checkpoint.EnteredAt
+	if result.Checkpoint.LastActivity.After(since) {
+		since = result.Checkpoint.LastActivity
+	}
+	var next *Transition
+	var at time.Time
+	for _, t := range m.transitionsFrom(result.State) {
+		if t.After <= 0 {
+			continue
+		}
+		if due := since.Add(t.After); next == nil || due.Before(at) {
+			next, at = t, due
+		}
+	}
+	return next, at
+}
+
+// takeTimed takes the timed transitions that are due before the given
+// time. The number of steps is bounded to protect against cycles of
+// timed transitions.
+func (m *Machine) takeTimed(result *Result, until time.Time) {
+	if result.Checkpoint.EnteredAt.IsZero() {
+		return
+	}
+	for i := 0; i <= len(m.Transitions); i++ {
+		t, at := m.nextTimed(result)
+		if t == nil || at.After(until) {
+			return
+		}
+		m.take(result, t, nil, at)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	utilclock "k8s.io/kubernetes/pkg/util/clock"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+const testTimedConfig = `
+initial: waiting
+states:
+- name: waiting
+  activity: {name: human-comment}
+- name: stale
+- name: rotten
+transitions:
+- from: waiting
+  to: stale
+  after: 1h
+- from: stale
+  to: rotten
+  after: 2h
+- from: stale
+  to: waiting
+  guard: {name: human-comment}
+`
+
+func TestTimedTransitions(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testTimedConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	start := time.Unix(0, 0)
+
+	tests := []struct {
+		name     string
+		now      time.Duration
+		comments []*githubapi.IssueComment
+		state    string
+		steps    int
+	}{
+		{
+			name:  "Not yet due",
+			now:   59 * time.Minute,
+			state: "waiting",
+		},
+		{
+			name:  "Due",
+			now:   time.Hour,
+			state: "stale",
+			steps: 1,
+		},
+		{
+			name:  "Chained",
+			now:   3 * time.Hour,
+			state: "rotten",
+			steps: 2,
+		},
+		{
+			name: "Activity resets the timer",
+			now:  90 * time.Minute,
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "user", start.Add(45*time.Minute), "ping"),
+			},
+			state: "waiting",
+		},
+		{
+			name: "Fires before later items",
+			now:  4 * time.Hour,
+			comments: []*githubapi.IssueComment{
+				github_test.Comment(1, "user", start.Add(2*time.Hour), "back"),
+			},
+			state: "stale",
+			steps: 3,
+		},
+	}
+
+	for _, test := range tests {
+		m.Clock = utilclock.NewFakeClock(start.Add(test.now))
+		issue := github_test.Issue("user", 1, nil, true)
+		issue.CreatedAt = &start
+		result, err := m.Process(issue, nil, test.comments)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.State != test.state || len(result.Steps) != test.steps {
+			t.Errorf("%s: expected %q in %d steps, got %q in %d steps",
+				test.name, test.state, test.steps, result.State, len(result.Steps))
+		}
+	}
+}
+
+func TestTimedTransitionErrors(t *testing.T) {
+	tests := []string{
+		"initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a}]",
+		"initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a, after: 1h, guard: {name: human-comment}}]",
+		"initial: a\nstates: [{name: a}]\ntransitions: [{from: a, to: a, after: soon}]",
+	}
+	for _, config := range tests {
+		if _, err := ReadMachine(strings.NewReader(config)); err == nil {
+			t.Errorf("Expected an error for %q", config)
+		}
+	}
+}
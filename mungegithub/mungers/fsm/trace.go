@@ -0,0 +1,100 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// String describes the item in a single line
+func (i *Item) String() string {
+	switch {
+	case i.Event != nil:
+		str := "event"
+		if i.Event.Event != nil {
+			str += " " + *i.Event.Event
+		}
+		if i.Event.Label != nil && i.Event.Label.Name != nil {
+			str += " " + *i.Event.Label.Name
+		}
+		if i.Event.Actor != nil && i.Event.Actor.Login != nil {
+			str += " by " + *i.Event.Actor.Login
+		}
+		return str
+	case i.Comment != nil:
+		str := "comment"
+		if i.Comment.User != nil && i.Comment.User.Login != nil {
+			str += " by " + *i.Comment.User.Login
+		}
+		if i.Comment.Body != nil {
+			body := strings.SplitN(strings.TrimSpace(*i.Comment.Body), "\n", 2)[0]
+			if len(body) > 60 {
+				body = body[:60] + "..."
+			}
+			str += fmt.Sprintf(": %q", body)
+		}
+		return str
+	case i.Review != nil:
+		str := "review"
+		if i.Review.State != nil {
+			str += " " + *i.Review.State
+		}
+		if i.Review.User != nil && i.Review.User.Login != nil {
+			str += " by " + *i.Review.User.Login
+		}
+		return str
+	}
+	return "<empty>"
+}
+
+// String describes the step and why it was taken
+func (s Step) String() string {
+	str := fmt.Sprintf("%s -> %s  ", s.From, s.To)
+	if s.Item == nil {
+		return str + fmt.Sprintf("after %v", s.Transition.After)
+	}
+	str += "on " + s.Item.String()
+	if s.Transition.Description != "" {
+		str += fmt.Sprintf(" (guard %s)", s.Transition.Description)
+	}
+	return str
+}
+
+// WriteTrace writes the trajectory of the issue through the machine,
+// followed by the actions that would be taken.
+func (r *Result) WriteTrace(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "Initial state: %s\n", r.Start)
+	for _, step := range r.Steps {
+		fmt.Fprintf(buf, "%s  %s\n", step.Time.UTC().Format(time.RFC3339), step)
+	}
+	fmt.Fprintf(buf, "Final state: %s\n", r.State)
+	if len(r.Actions) == 0 {
+		fmt.Fprintln(buf, "No action needed")
+	} else {
+		fmt.Fprintln(buf, "Actions:")
+		for _, a := range r.Actions {
+			fmt.Fprintf(buf, "  %s\n", a)
+		}
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+func TestWriteTrace(t *testing.T) {
+	m, err := ReadMachine(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	comments := []*githubapi.IssueComment{
+		github_test.Comment(1, "user", time.Unix(10, 0), "/rebase"),
+	}
+	result, err := m.Process(github_test.Issue("user", 1, nil, true), nil, comments)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := result.WriteTrace(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `Initial state: needs-review
+1970-01-01T00:00:10Z  needs-review -> needs-rebase  on comment by user: "/rebase" (guard command(rebase))
+Final state: needs-rebase
+Actions:
+  add label state/needs-rebase
+  add label do-not-merge
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"fmt"
+)
+
+// Validate looks for likely mistakes in a machine that is otherwise
+// well-formed: states that can't be reached, states that can't be left,
+// and transitions that can never be taken because another transition
+// with the same guard, or a shorter delay, is always taken first.
+// References to unknown guards, actions and hooks are already rejected
+// by ReadMachine.
+func (m *Machine) Validate() []string {
+	warnings := []string{}
+	seen := map[string]bool{}
+	warn := func(format string, args ...interface{}) {
+		w := fmt.Sprintf(format, args...)
+		if !seen[w] {
+			seen[w] = true
+			warnings = append(warnings, w)
+		}
+	}
+
+	reachable := m.reachable()
+	for _, s := range m.States {
+		if !reachable[s.Name] {
+			warn("state %q is unreachable", s.Name)
+		}
+	}
+
+	for _, s := range m.States {
+		if m.isComposite(s.Name) {
+			continue
+		}
+		transitions := m.transitionsFrom(s.Name)
+		if len(transitions) == 0 {
+			warn("state %q has no exit", s.Name)
+		}
+
+		var shortest *Transition
+		byGuard := map[string]*Transition{}
+		for _, t := range transitions {
+			if t.After > 0 {
+				if shortest == nil {
+					shortest = t
+				} else if t.After >= shortest.After {
+					warn("transition %s is never taken from %q, %s fires first", describe(t), s.Name, describe(shortest))
+				} else {
+					shortest = t
+				}
+				continue
+			}
+			if t.Description == "" {
+				continue
+			}
+			if first, ok := byGuard[t.Description]; ok {
+				warn("transition %s is never taken from %q, %s has the same guard", describe(t), s.Name, describe(first))
+				continue
+			}
+			byGuard[t.Description] = t
+		}
+	}
+	return warnings
+}
+
+// reachable returns the states the machine can be in, starting from the
+// initial state. Ancestors of reachable states are reachable.
+func (m *Machine) reachable() map[string]bool {
+	reachable := map[string]bool{}
+	queue := []string{m.leaf(m.Initial)}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if reachable[state] {
+			continue
+		}
+		for _, a := range m.ancestors(state) {
+			reachable[a] = true
+		}
+		for _, t := range m.transitionsFrom(state) {
+			queue = append(queue, m.leaf(t.To))
+		}
+	}
+	return reachable
+}
+
+func describe(t *Transition) string {
+	return fmt.Sprintf("%s -> %s (%s)", t.From, t.To, t.Description)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		warnings []string
+	}{
+		{
+			name:     "Review preset",
+			config:   reviewPreset,
+			warnings: []string{},
+		},
+		{
+			name:     "Hierarchy",
+			config:   testHierarchyConfig,
+			warnings: []string{},
+		},
+		{
+			name: "Unreachable and no exit",
+			config: `
+initial: a
+states: [{name: a}, {name: b}, {name: c}]
+transitions:
+- {from: a, to: b, guard: {name: label-added, args: [lgtm]}}
+- {from: c, to: a, guard: {name: label-added, args: [lgtm]}}
+`,
+			warnings: []string{
+				`state "c" is unreachable`,
+				`state "b" has no exit`,
+			},
+		},
+		{
+			name: "Shadowed transitions",
+			config: `
+initial: p
+states: [{name: p, initial: a}, {name: a, parent: p}, {name: b}, {name: c}]
+transitions:
+- {from: a, to: b, guard: {name: label-added, args: [lgtm]}}
+- {from: p, to: c, guard: {name: label-added, args: [lgtm]}}
+- {from: a, to: b, after: 1h}
+- {from: a, to: c, after: 2h}
+- {from: b, to: a, guard: {name: human-comment}}
+- {from: c, to: a, guard: {name: human-comment}}
+`,
+			warnings: []string{
+				`transition a -> c (after 2h) is never taken from "a", a -> b (after 1h) fires first`,
+				`transition p -> c (label-added(lgtm)) is never taken from "a", a -> b (label-added(lgtm)) has the same guard`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		m, err := ReadMachine(strings.NewReader(test.config))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if warnings := m.Validate(); !reflect.DeepEqual(warnings, test.warnings) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.warnings, warnings)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/contrib/mungegithub/features"
+	mgh "k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+	"k8s.io/contrib/mungegithub/mungers/mungerutil"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	inactiveAssigneeNotifyName   = "INACTIVE-ASSIGNEE"
+	inactiveAssigneeUnassignName = "INACTIVE-ASSIGNEE-UNASSIGNED"
+)
+
+// InactiveAssigneeMunger pings the assignees who haven't commented or
+// reviewed since they were assigned, every `pingPeriod`. They are
+// unassigned `deadline` after the assignment if they still haven't.
+type InactiveAssigneeMunger struct {
+	pingPeriod time.Duration
+	deadline   time.Duration
+	pinger     *c.Pinger
+}
+
+var _ Munger = &InactiveAssigneeMunger{}
+
+func init() {
+	i := &InactiveAssigneeMunger{}
+	RegisterMungerOrDie(i)
+	RegisterStaleComments(i)
+}
+
+// Name is the name usable in --pr-mungers
+func (i *InactiveAssigneeMunger) Name() string { return "inactive-assignee" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (i *InactiveAssigneeMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (i *InactiveAssigneeMunger) Initialize(config *mgh.Config, features *features.Features) error {
+	if i.pingPeriod >= i.deadline {
+		return fmt.Errorf("--inactive-assignee-ping-period (%v) must be shorter than --inactive-assignee-deadline (%v)", i.pingPeriod, i.deadline)
+	}
+	i.pinger = c.NewPinger(inactiveAssigneeNotifyName).
+		SetDescription("This is assigned to you, but you haven't commented or reviewed since. Please comment to let others know you are still working on it, or unassign yourself.").
+		SetTimePeriod(i.pingPeriod)
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (i *InactiveAssigneeMunger) EachLoop() error { return nil }
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (i *InactiveAssigneeMunger) AddFlags(cmd *cobra.Command, config *mgh.Config) {
+	cmd.Flags().DurationVar(&i.pingPeriod, "inactive-assignee-ping-period", 7*day, "How often assignees who haven't commented or reviewed since the assignment are pinged")
+	cmd.Flags().DurationVar(&i.deadline, "inactive-assignee-deadline", 30*day, "Time after the assignment before assignees who haven't commented or reviewed are unassigned")
+}
+
+// assignedAt returns when `login` was last assigned to the issue
+func assignedAt(issue *github.Issue, events []*github.IssueEvent, login string) time.Time {
+	created := time.Time{}
+	if issue.CreatedAt != nil {
+		created = *issue.CreatedAt
+	}
+	return *e.LastEvent(events, e.And([]e.Matcher{e.AddAssignee{}, e.Assignee(login)}), &created)
+}
+
+// assigneeActivity is what an assignee did on the issue: comments, and
+// reviews and review comments if it is a PR.
+type assigneeActivity struct {
+	comments       []*github.IssueComment
+	reviews        []*mgh.PullRequestReview
+	reviewComments []*github.PullRequestComment
+}
+
+func listAssigneeActivity(obj *mgh.MungeObject) (*assigneeActivity, error) {
+	comments, err := obj.ListComments()
+	if err != nil {
+		return nil, err
+	}
+	activity := &assigneeActivity{comments: comments}
+	if !obj.IsPR() {
+		return activity, nil
+	}
+	if activity.reviews, err = obj.ListReviews(); err != nil {
+		return nil, err
+	}
+	if activity.reviewComments, err = obj.ListReviewComments(); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// last returns when `login` last commented or reviewed
+func (a *assigneeActivity) last(login string) time.Time {
+	last := *c.LastComment(a.comments, c.AuthorLogin(login), &time.Time{})
+	for _, review := range a.reviews {
+		if review.User == nil || !strings.EqualFold(stringValue(review.User.Login), login) || review.SubmittedAt == nil {
+			continue
+		}
+		if review.SubmittedAt.After(last) {
+			last = *review.SubmittedAt
+		}
+	}
+	for _, comment := range a.reviewComments {
+		if comment.User == nil || !strings.EqualFold(stringValue(comment.User.Login), login) || comment.CreatedAt == nil {
+			continue
+		}
+		if comment.CreatedAt.After(last) {
+			last = *comment.CreatedAt
+		}
+	}
+	return last
+}
+
+// pingsFor matches the pings sent to `login`
+func pingsFor(login string) c.Matcher {
+	return c.And([]c.Matcher{
+		c.MungerNotificationName(inactiveAssigneeNotifyName),
+		c.NotificationArguments(*regexp.MustCompile(`(?i)^@` + regexp.QuoteMeta(login) + `$`)),
+	})
+}
+
+// notification returns the notification to post for the assignee
+// `login` at time `now` (if any), and whether they must be unassigned.
+func (i *InactiveAssigneeMunger) notification(issue *github.Issue, events []*github.IssueEvent, activity *assigneeActivity, login string, now time.Time) (*c.Notification, bool) {
+	since := assignedAt(issue, events, login)
+	if activity.last(login).After(since) {
+		return nil, false
+	}
+
+	if now.Sub(since) >= i.deadline {
+		return &c.Notification{
+			Name:      inactiveAssigneeUnassignName,
+			Arguments: "@" + login,
+			Context: fmt.Sprintf(
+				"Unassigning as you haven't commented or reviewed in the %s since you were assigned. Feel free to assign yourself again when you get back to it.",
+				durationToDays(now.Sub(since)),
+			),
+		}, true
+	}
+
+	notif := i.pinger.PingNotification(c.FilterComments(activity.comments, pingsFor(login)), "@"+login, &since)
+	if notif != nil {
+		notif.Context += fmt.Sprintf(" You will be unassigned in %s.", durationToDays(i.deadline-now.Sub(since)))
+	}
+	return notif, false
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (i *InactiveAssigneeMunger) Munge(obj *mgh.MungeObject) {
+	assignees := mungerutil.GetIssueUsers(obj.Issue).Assignees
+	if len(assignees) == 0 {
+		return
+	}
+
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	activity, err := listAssigneeActivity(obj)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	now := time.Now()
+	for _, login := range assignees.List() {
+		notif, unassign := i.notification(obj.Issue, events, activity, login, now)
+		if notif == nil {
+			continue
+		}
+		if unassign && obj.UnassignPR(login) != nil {
+			continue
+		}
+		notif.Post(obj)
+	}
+}
+
+// StaleComments returns a slice of stale comments
+func (i *InactiveAssigneeMunger) StaleComments(obj *mgh.MungeObject, comments []*github.IssueComment) []*github.IssueComment {
+	// Pings are obsolete once the assignee has commented or reviewed, or is gone
+	pings := c.FilterComments(comments, c.MungerNotificationName(inactiveAssigneeNotifyName))
+	if pings.Empty() {
+		return nil
+	}
+	activity, err := listAssigneeActivity(obj)
+	if err != nil {
+		glog.Error(err)
+		return nil
+	}
+	assignees := mungerutil.GetIssueUsers(obj.Issue).Assignees
+	stale := []*github.IssueComment{}
+	for _, ping := range pings {
+		login := strings.TrimPrefix(c.ParseNotification(ping).Arguments, "@")
+		if !assignees.Has(&github.User{Login: &login}) || activity.last(login).After(*ping.CreatedAt) {
+			stale = append(stale, ping)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+
+	mgh "k8s.io/contrib/mungegithub/github"
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+func makeAssignEvent(assignee string, at time.Time) *github.IssueEvent {
+	event := "assigned"
+	return &github.IssueEvent{
+		Event:     &event,
+		Assignee:  &github.User{Login: &assignee},
+		CreatedAt: &at,
+	}
+}
+
+func makeReview(reviewer string, at time.Time) *mgh.PullRequestReview {
+	state := mgh.ReviewCommented
+	return &mgh.PullRequestReview{
+		User:        &github.User{Login: &reviewer},
+		State:       &state,
+		SubmittedAt: &at,
+	}
+}
+
+func makeReviewComment(reviewer string, at time.Time) *github.PullRequestComment {
+	return &github.PullRequestComment{
+		User:      &github.User{Login: &reviewer},
+		CreatedAt: &at,
+	}
+}
+
+func TestAssignedAtWithoutCreationDate(t *testing.T) {
+	issue := github_test.Issue("user", 1, nil, false)
+	issue.CreatedAt = nil
+	if at := assignedAt(issue, nil, "assignee"); !at.IsZero() {
+		t.Errorf("Expected zero time, got %v", at)
+	}
+}
+
+func TestInactiveAssigneeNotification(t *testing.T) {
+	// The pinger works with the current time
+	now := time.Now()
+	ago := func(days int) time.Time { return now.Add(-time.Duration(days) * day) }
+
+	i := &InactiveAssigneeMunger{pingPeriod: 7 * day, deadline: 30 * day}
+	if err := i.Initialize(nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		events         []*github.IssueEvent
+		comments       []*github.IssueComment
+		reviews        []*mgh.PullRequestReview
+		reviewComments []*github.PullRequestComment
+		notify         string
+		unassign       bool
+	}{
+		{
+			name:   "Just assigned",
+			events: []*github.IssueEvent{makeAssignEvent("assignee", ago(2))},
+		},
+		{
+			name:   "Ping after the period",
+			events: []*github.IssueEvent{makeAssignEvent("assignee", ago(8))},
+			notify: inactiveAssigneeNotifyName,
+		},
+		{
+			name:     "Assignee commented",
+			events:   []*github.IssueEvent{makeAssignEvent("assignee", ago(20))},
+			comments: []*github.IssueComment{github_test.Comment(1, "assignee", ago(19), "On it")},
+		},
+		{
+			name:    "Assignee reviewed",
+			events:  []*github.IssueEvent{makeAssignEvent("assignee", ago(20))},
+			reviews: []*mgh.PullRequestReview{makeReview("Assignee", ago(19))},
+		},
+		{
+			name:           "Assignee left a review comment",
+			events:         []*github.IssueEvent{makeAssignEvent("assignee", ago(20))},
+			reviewComments: []*github.PullRequestComment{makeReviewComment("assignee", ago(19))},
+		},
+		{
+			name:           "Review before the assignment doesn't count",
+			events:         []*github.IssueEvent{makeAssignEvent("assignee", ago(20))},
+			reviews:        []*mgh.PullRequestReview{makeReview("assignee", ago(25))},
+			reviewComments: []*github.PullRequestComment{makeReviewComment("assignee", ago(25))},
+			notify:         inactiveAssigneeNotifyName,
+		},
+		{
+			name:    "Review by someone else doesn't count",
+			events:  []*github.IssueEvent{makeAssignEvent("assignee", ago(20))},
+			reviews: []*mgh.PullRequestReview{makeReview("other", ago(19))},
+			notify:  inactiveAssigneeNotifyName,
+		},
+		{
+			name:     "Comment before the assignment doesn't count",
+			events:   []*github.IssueEvent{makeAssignEvent("assignee", ago(20))},
+			comments: []*github.IssueComment{github_test.Comment(1, "assignee", ago(25), "I can take it")},
+			notify:   inactiveAssigneeNotifyName,
+		},
+		{
+			name:     "Already pinged",
+			events:   []*github.IssueEvent{makeAssignEvent("assignee", ago(10))},
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", ago(3), "[INACTIVE-ASSIGNEE] @assignee")},
+		},
+		{
+			name:     "Ping for another assignee",
+			events:   []*github.IssueEvent{makeAssignEvent("assignee", ago(10))},
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", ago(3), "[INACTIVE-ASSIGNEE] @other")},
+			notify:   inactiveAssigneeNotifyName,
+		},
+		{
+			name:     "Unassign after the deadline",
+			events:   []*github.IssueEvent{makeAssignEvent("assignee", ago(31))},
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", ago(3), "[INACTIVE-ASSIGNEE] @assignee")},
+			notify:   inactiveAssigneeUnassignName,
+			unassign: true,
+		},
+		{
+			name: "Reassignment restarts the timer",
+			events: []*github.IssueEvent{
+				makeAssignEvent("assignee", ago(40)),
+				makeAssignEvent("assignee", ago(1)),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, nil, false)
+		created := ago(60)
+		issue.CreatedAt = &created
+		activity := &assigneeActivity{comments: test.comments, reviews: test.reviews, reviewComments: test.reviewComments}
+		notif, unassign := i.notification(issue, test.events, activity, "assignee", now)
+		name := ""
+		if notif != nil {
+			name = notif.Name
+		}
+		if name != test.notify {
+			t.Errorf("%s: expected notification %q, got %q", test.name, test.notify, name)
+		}
+		if unassign != test.unassign {
+			t.Errorf("%s: expected unassign %v, got %v", test.name, test.unassign, unassign)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/util/yaml"
+)
+
+// repoLabelAccessor manages the labels of any repository
+type repoLabelAccessor interface {
+	ListRepoLabels(org, repo string) ([]*github.RepoLabel, error)
+	CreateRepoLabel(org, repo string, label *github.RepoLabel) error
+	EditRepoLabel(org, repo, name string, label *github.RepoLabel) error
+	DeleteRepoLabel(org, repo, name string) error
+}
+
+// labelSyncFile is the canonical definition of the labels
+type labelSyncFile struct {
+	Labels []*github.RepoLabel `json:"labels"`
+}
+
+// labelChanges are the operations needed to reconcile a repository
+type labelChanges struct {
+	create []*github.RepoLabel
+	// update maps the current name of the label to its definition
+	update map[string]*github.RepoLabel
+	remove []string
+}
+
+// LabelSyncMunger reconciles the labels (names, colors and descriptions)
+// of each repository with a canonical YAML file. Labels missing from the
+// file are only deleted with --label-sync-delete.
+type LabelSyncMunger struct {
+	File   string
+	Repos  []string
+	Delete bool
+	access repoLabelAccessor
+}
+
+func init() {
+	RegisterMungerOrDie(&LabelSyncMunger{})
+}
+
+// Name is the name usable in --pr-mungers
+func (l *LabelSyncMunger) Name() string { return "label-sync" }
+
+// RequiredFeatures is a slice of 'features' that must be provided.
+func (l *LabelSyncMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger.
+func (l *LabelSyncMunger) Initialize(config *github.Config, features *features.Features) error {
+	if len(l.File) == 0 {
+		return fmt.Errorf("no --label-sync-file supplied, cannot sync labels")
+	}
+	if len(l.Repos) == 0 {
+		l.Repos = []string{config.Org + "/" + config.Project}
+	}
+	for _, repo := range l.Repos {
+		if len(strings.Split(repo, "/")) != 2 {
+			return fmt.Errorf("invalid repository %q in --label-sync-repos, expected org/repo", repo)
+		}
+	}
+	l.access = config
+	return nil
+}
+
+// EachLoop reconciles all the repositories
+func (l *LabelSyncMunger) EachLoop() error {
+	labels, err := readLabelSyncFile(l.File)
+	if err != nil {
+		return err
+	}
+	for _, repo := range l.Repos {
+		parts := strings.Split(repo, "/")
+		if err := l.sync(parts[0], parts[1], labels); err != nil {
+			glog.Errorf("Failed to sync labels of %s: %v", repo, err)
+		}
+	}
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`.
+func (l *LabelSyncMunger) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().StringVar(&l.File, "label-sync-file", "", "YAML file with the canonical name, color and description of the labels")
+	cmd.Flags().StringSliceVar(&l.Repos, "label-sync-repos", []string{}, "Repositories (org/repo) to sync the labels of, defaults to the munged repository")
+	cmd.Flags().BoolVar(&l.Delete, "label-sync-delete", false, "Delete the labels that are not in --label-sync-file")
+}
+
+// Munge is unused by this munger.
+func (l *LabelSyncMunger) Munge(obj *github.MungeObject) {}
+
+func readLabelSyncFile(path string) ([]*github.RepoLabel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	c := labelSyncFile{}
+	if err := yaml.NewYAMLToJSONDecoder(file).Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	// Github label names are case insensitive
+	names := sets.NewString()
+	for i, label := range c.Labels {
+		if label.Name == nil || *label.Name == "" {
+			return nil, fmt.Errorf("%s: label %d has no name", path, i)
+		}
+		if names.Has(strings.ToLower(*label.Name)) {
+			return nil, fmt.Errorf("%s: label %s is defined more than once", path, *label.Name)
+		}
+		names.Insert(strings.ToLower(*label.Name))
+		// Github can't create a label without a color
+		if label.Color == nil || *label.Color == "" {
+			return nil, fmt.Errorf("%s: label %s has no color", path, *label.Name)
+		}
+	}
+	return c.Labels, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// diffLabels compares labels ignoring the case of names and colors, as
+// Github does. The description is left alone if the wanted label has none.
+func diffLabels(current, wanted []*github.RepoLabel, remove bool) labelChanges {
+	changes := labelChanges{update: map[string]*github.RepoLabel{}}
+	existing := map[string]*github.RepoLabel{}
+	for _, label := range current {
+		existing[strings.ToLower(stringValue(label.Name))] = label
+	}
+	for _, label := range wanted {
+		key := strings.ToLower(*label.Name)
+		old, found := existing[key]
+		delete(existing, key)
+		switch {
+		case !found:
+			changes.create = append(changes.create, label)
+		case *old.Name != *label.Name ||
+			!strings.EqualFold(stringValue(old.Color), stringValue(label.Color)) ||
+			label.Description != nil && stringValue(old.Description) != *label.Description:
+			changes.update[*old.Name] = label
+		}
+	}
+	if remove {
+		for _, label := range current {
+			if _, ok := existing[strings.ToLower(stringValue(label.Name))]; ok {
+				changes.remove = append(changes.remove, *label.Name)
+			}
+		}
+	}
+	return changes
+}
+
+func (l *LabelSyncMunger) sync(org, repo string, wanted []*github.RepoLabel) error {
+	current, err := l.access.ListRepoLabels(org, repo)
+	if err != nil {
+		return err
+	}
+	changes := diffLabels(current, wanted, l.Delete)
+	for _, label := range changes.create {
+		if err := l.access.CreateRepoLabel(org, repo, label); err != nil {
+			glog.Errorf("Failed to create label %s in %s/%s: %v", *label.Name, org, repo, err)
+		}
+	}
+	for name, label := range changes.update {
+		if err := l.access.EditRepoLabel(org, repo, name, label); err != nil {
+			glog.Errorf("Failed to update label %s in %s/%s: %v", name, org, repo, err)
+		}
+	}
+	for _, name := range changes.remove {
+		if err := l.access.DeleteRepoLabel(org, repo, name); err != nil {
+			glog.Errorf("Failed to delete label %s from %s/%s: %v", name, org, repo, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/contrib/mungegithub/github"
+)
+
+type fakeRepoLabels struct {
+	labels  map[string][]*github.RepoLabel
+	changes []string
+}
+
+func (f *fakeRepoLabels) ListRepoLabels(org, repo string) ([]*github.RepoLabel, error) {
+	return f.labels[org+"/"+repo], nil
+}
+
+func (f *fakeRepoLabels) CreateRepoLabel(org, repo string, label *github.RepoLabel) error {
+	f.changes = append(f.changes, "create "+org+"/"+repo+" "+*label.Name)
+	return nil
+}
+
+func (f *fakeRepoLabels) EditRepoLabel(org, repo, name string, label *github.RepoLabel) error {
+	f.changes = append(f.changes, "edit "+org+"/"+repo+" "+name+" -> "+*label.Name+" "+*label.Color+" "+stringValue(label.Description))
+	return nil
+}
+
+func (f *fakeRepoLabels) DeleteRepoLabel(org, repo, name string) error {
+	f.changes = append(f.changes, "delete "+org+"/"+repo+" "+name)
+	return nil
+}
+
+func repoLabel(name, color, description string) *github.RepoLabel {
+	return &github.RepoLabel{Name: &name, Color: &color, Description: &description}
+}
+
+func TestLabelSync(t *testing.T) {
+	file, err := ioutil.TempFile("", "labels")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`labels:
+- name: lgtm
+  color: 15dd18
+  description: Looks good to me
+- name: sig/node
+  color: d2b48c
+- name: kind/bug
+  color: e11d21
+`)
+	file.Close()
+
+	tests := []struct {
+		name     string
+		current  []*github.RepoLabel
+		delete   bool
+		expected []string
+	}{
+		{
+			name:    "Up to date",
+			current: []*github.RepoLabel{repoLabel("lgtm", "15DD18", "Looks good to me"), repoLabel("sig/node", "d2b48c", ""), repoLabel("kind/bug", "e11d21", "")},
+		},
+		{
+			name:    "Create missing labels",
+			current: []*github.RepoLabel{repoLabel("lgtm", "15dd18", "Looks good to me")},
+			expected: []string{
+				"create o/r kind/bug",
+				"create o/r sig/node",
+			},
+		},
+		{
+			name:    "Update color, description and case",
+			current: []*github.RepoLabel{repoLabel("LGTM", "15dd18", "Looks good"), repoLabel("sig/node", "ffffff", ""), repoLabel("kind/bug", "e11d21", "")},
+			expected: []string{
+				"edit o/r LGTM -> lgtm 15dd18 Looks good to me",
+				"edit o/r sig/node -> sig/node d2b48c ",
+			},
+		},
+		{
+			name:    "Keep descriptions missing from the file",
+			current: []*github.RepoLabel{repoLabel("lgtm", "15dd18", "Looks good to me"), repoLabel("sig/node", "d2b48c", "Node"), repoLabel("kind/bug", "e11d21", "A bug")},
+		},
+		{
+			name:    "Keep unknown labels",
+			current: []*github.RepoLabel{repoLabel("lgtm", "15dd18", "Looks good to me"), repoLabel("sig/node", "d2b48c", ""), repoLabel("kind/bug", "e11d21", ""), repoLabel("old", "000000", "")},
+		},
+		{
+			name:     "Delete unknown labels",
+			current:  []*github.RepoLabel{repoLabel("lgtm", "15dd18", "Looks good to me"), repoLabel("sig/node", "d2b48c", ""), repoLabel("kind/bug", "e11d21", ""), repoLabel("old", "000000", "")},
+			delete:   true,
+			expected: []string{"delete o/r old"},
+		},
+	}
+	for _, test := range tests {
+		fake := &fakeRepoLabels{labels: map[string][]*github.RepoLabel{"o/r": test.current}}
+		l := &LabelSyncMunger{File: file.Name(), Repos: []string{"o/r"}, Delete: test.delete, access: fake}
+		if err := l.EachLoop(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		sort.Strings(fake.changes)
+		if len(fake.changes) == 0 && len(test.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(fake.changes, test.expected) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, fake.changes)
+		}
+	}
+}
+
+func TestReadLabelSyncFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{
+			name:    "No description",
+			content: "labels:\n- name: lgtm\n  color: 15dd18\n",
+			valid:   true,
+		},
+		{
+			name:    "No name",
+			content: "labels:\n- color: 15dd18\n",
+		},
+		{
+			name:    "No color",
+			content: "labels:\n- name: lgtm\n  description: Looks good to me\n",
+		},
+		{
+			name:    "Empty color",
+			content: "labels:\n- name: lgtm\n  color: \"\"\n",
+		},
+		{
+			name:    "Duplicate name",
+			content: "labels:\n- name: lgtm\n  color: 15dd18\n- name: lgtm\n  color: 15dd18\n",
+		},
+		{
+			name:    "Duplicate name with another case",
+			content: "labels:\n- name: lgtm\n  color: 15dd18\n- name: LGTM\n  color: 15dd18\n",
+		},
+	}
+	for _, test := range tests {
+		file, err := ioutil.TempFile("", "labels")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		file.WriteString(test.content)
+		file.Close()
+		_, err = readLabelSyncFile(file.Name())
+		os.Remove(file.Name())
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+		}
+	}
+}
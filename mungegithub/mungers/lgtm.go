@@ -0,0 +1,144 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"regexp"
+	"time"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	lgtmCommand = "lgtm"
+	// lgtmPermission is the permission needed on the repository to /lgtm
+	lgtmPermission = "push"
+)
+
+var lgtmCancelRegex = regexp.MustCompile(`(?i)^cancel\b`)
+
+// LGTMMunger adds the LGTM label when someone with push access comments
+// `/lgtm`, and removes it on `/lgtm cancel`. As for lgtm-after-commit,
+// the label is also removed when the PR changes after the approval.
+type LGTMMunger struct {
+	config     *github.Config
+	permission c.Matcher
+}
+
+func init() {
+	RegisterMungerOrDie(&LGTMMunger{})
+}
+
+// Name is the name usable in --pr-mungers
+func (l *LGTMMunger) Name() string { return "lgtm" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (l *LGTMMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (l *LGTMMunger) Initialize(config *github.Config, features *features.Features) error {
+	l.config = config
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (l *LGTMMunger) EachLoop() error {
+	pushUsers, _, err := l.config.UsersWithAccess()
+	if err != nil {
+		return err
+	}
+	l.permission = c.NewPermission(pushUsers, lgtmPermission)
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (l *LGTMMunger) AddFlags(cmd *cobra.Command, config *github.Config) {}
+
+// lastLGTMCommand returns the last `/lgtm` or `/lgtm cancel` written by
+// someone with the `permission`, or nil. Authors can cancel but can't
+// approve their own PR.
+func lastLGTMCommand(comments []*githubapi.IssueComment, permission c.Matcher, author string) *githubapi.IssueComment {
+	return c.FilterComments(comments, c.And([]c.Matcher{
+		c.HumanActor(),
+		c.CommandName(lgtmCommand),
+		permission,
+		c.Or([]c.Matcher{
+			c.CommandArguments(*lgtmCancelRegex),
+			c.Not{Matcher: c.AuthorLogin(author)},
+		}),
+	})).GetLast()
+}
+
+func isLGTMCancel(comment *githubapi.IssueComment) bool {
+	command := c.ParseCommand(comment)
+	return command != nil && lgtmCancelRegex.MatchString(command.Arguments)
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (l *LGTMMunger) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() || l.permission == nil || obj.Issue.User == nil || obj.Issue.User.Login == nil {
+		return
+	}
+
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	// Commands older than the last change of the label have already been
+	// applied, or were overridden by someone changing the label by hand.
+	command := lastLGTMCommand(comments, l.permission, *obj.Issue.User.Login)
+	labelChanged := e.LastEvent(events, e.And([]e.Matcher{
+		e.Or([]e.Matcher{e.AddLabel{}, e.RemoveLabel{}}),
+		e.LabelName(lgtmLabel),
+	}), &time.Time{})
+	if command != nil && command.CreatedAt.After(*labelChanged) {
+		switch {
+		case isLGTMCancel(command) && obj.HasLabel(lgtmLabel):
+			obj.RemoveLabel(lgtmLabel)
+			return
+		case !isLGTMCancel(command) && !obj.HasLabel(lgtmLabel):
+			if changed, err := prChangedSince(obj, *command.CreatedAt); err != nil || changed {
+				return
+			}
+			obj.AddLabel(lgtmLabel)
+			return
+		}
+	}
+
+	if !obj.HasLabel(lgtmLabel) {
+		return
+	}
+	lgtmTime := obj.LabelTime(lgtmLabel)
+	if lgtmTime == nil {
+		return
+	}
+	removeLGTMIfChanged(obj, *lgtmTime)
+}
@@ -19,6 +19,7 @@ package mungers
 import (
 	"fmt"
 	"regexp"
+	"time"
 
 	"k8s.io/contrib/mungegithub/features"
 	"k8s.io/contrib/mungegithub/github"
@@ -30,7 +31,8 @@ import (
 )
 
 const (
-	lgtmRemovedBody = "/lgtm cancel //PR changed after LGTM, removing LGTM. %s"
+	lgtmRemovedBody  = "/lgtm cancel //PR changed after LGTM, removing LGTM. %s"
+	forcePushedEvent = "head_ref_force_pushed"
 )
 
 var (
@@ -74,22 +76,60 @@ func (LGTMAfterCommitMunger) Munge(obj *github.MungeObject) {
 		return
 	}
 
-	lastModified := obj.LastModifiedTime()
 	lgtmTime := obj.LabelTime(lgtmLabel)
-
-	if lastModified == nil || lgtmTime == nil {
-		glog.Errorf("PR %d unable to determine lastModified or lgtmTime", *obj.Issue.Number)
+	if lgtmTime == nil {
+		glog.Errorf("PR %d unable to determine lgtmTime", *obj.Issue.Number)
 		return
 	}
 
-	if lastModified.After(*lgtmTime) {
-		glog.Infof("PR: %d lgtm:%s  lastModified:%s", *obj.Issue.Number, lgtmTime.String(), lastModified.String())
-		body := fmt.Sprintf(lgtmRemovedBody, mungerutil.GetIssueUsers(obj.Issue).AllUsers().Mention().Join())
-		if err := obj.WriteComment(body); err != nil {
-			return
+	removeLGTMIfChanged(obj, *lgtmTime)
+}
+
+// prChangedSince returns true if commits were pushed, or force pushed, to
+// the PR after `since`.
+func prChangedSince(obj *github.MungeObject, since time.Time) (bool, error) {
+	lastModified := obj.LastModifiedTime()
+	if lastModified == nil {
+		return false, fmt.Errorf("unable to determine lastModified")
+	}
+	if lastModified.After(since) {
+		return true, nil
+	}
+
+	// A force push can bring older commits, look at the events too.
+	events, err := obj.GetEvents()
+	if err != nil {
+		return false, err
+	}
+	for _, event := range events {
+		if event.Event == nil || *event.Event != forcePushedEvent || event.CreatedAt == nil {
+			continue
 		}
-		obj.RemoveLabel(lgtmLabel)
+		if event.CreatedAt.After(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// removeLGTMIfChanged removes the LGTM label, with a comment, if the PR
+// changed after it was approved at `lgtmTime`.
+func removeLGTMIfChanged(obj *github.MungeObject, lgtmTime time.Time) {
+	changed, err := prChangedSince(obj, lgtmTime)
+	if err != nil {
+		glog.Errorf("PR %d: %v", *obj.Issue.Number, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	glog.Infof("PR: %d changed after lgtm:%s", *obj.Issue.Number, lgtmTime.String())
+	body := fmt.Sprintf(lgtmRemovedBody, mungerutil.GetIssueUsers(obj.Issue).AllUsers().Mention().Join())
+	if err := obj.WriteComment(body); err != nil {
+		return
 	}
+	obj.RemoveLabel(lgtmLabel)
 }
 
 func (LGTMAfterCommitMunger) isStaleComment(obj *github.MungeObject, comment *githubapi.IssueComment) bool {
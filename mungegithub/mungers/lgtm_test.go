@@ -0,0 +1,91 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/google/go-github/github"
+)
+
+func TestLastLGTMCommand(t *testing.T) {
+	permissions := map[string]bool{"push": true}
+	reviewer := "reviewer"
+	author := "author"
+	permission := c.NewPermission([]*github.User{
+		{Login: &reviewer, Permissions: &permissions},
+		{Login: &author, Permissions: &permissions},
+	}, lgtmPermission)
+
+	tests := []struct {
+		name     string
+		comments []*github.IssueComment
+		expected int
+		cancel   bool
+	}{
+		{
+			name:     "No command",
+			comments: []*github.IssueComment{github_test.Comment(1, "reviewer", time.Unix(10, 0), "looks good")},
+		},
+		{
+			name:     "LGTM",
+			comments: []*github.IssueComment{github_test.Comment(1, "reviewer", time.Unix(10, 0), "/lgtm")},
+			expected: 1,
+		},
+		{
+			name:     "No permission",
+			comments: []*github.IssueComment{github_test.Comment(1, "stranger", time.Unix(10, 0), "/lgtm")},
+		},
+		{
+			name:     "Author can't approve",
+			comments: []*github.IssueComment{github_test.Comment(1, "author", time.Unix(10, 0), "/lgtm")},
+		},
+		{
+			name:     "Author can cancel",
+			comments: []*github.IssueComment{github_test.Comment(1, "author", time.Unix(10, 0), "/lgtm cancel")},
+			expected: 1,
+			cancel:   true,
+		},
+		{
+			name: "Last command wins",
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "reviewer", time.Unix(10, 0), "/lgtm"),
+				github_test.Comment(2, "reviewer", time.Unix(20, 0), "/LGTM Cancel"),
+				github_test.Comment(3, "stranger", time.Unix(30, 0), "/lgtm"),
+			},
+			expected: 2,
+			cancel:   true,
+		},
+	}
+	for _, test := range tests {
+		comment := lastLGTMCommand(test.comments, permission, author)
+		id := 0
+		if comment != nil {
+			id = *comment.ID
+		}
+		if id != test.expected {
+			t.Errorf("%s: expected comment %d, got %d", test.name, test.expected, id)
+		}
+		if comment != nil && isLGTMCancel(comment) != test.cancel {
+			t.Errorf("%s: expected cancel %v, got %v", test.name, test.cancel, !test.cancel)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/contrib/mungegithub/features"
+	mgh "k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+	"k8s.io/contrib/mungegithub/mungers/mungerutil"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+const (
+	lifecycleStaleLabel  = "lifecycle/stale"
+	lifecycleRottenLabel = "lifecycle/rotten"
+	lifecycleFrozenLabel = "lifecycle/frozen"
+	lifecycleLabelPrefix = "lifecycle/"
+
+	lifecycleStaleNotifyName  = "LIFECYCLE-STALE"
+	lifecycleRottenNotifyName = "LIFECYCLE-ROTTEN"
+	lifecycleCloseNotifyName  = "LIFECYCLE-CLOSE"
+
+	lifecycleRemoveCommand = "remove-lifecycle"
+)
+
+// lifecycleStage is one step of the lifecycle. Stages are applied in
+// order, each one `after` the previous one without human activity.
+type lifecycleStage struct {
+	// label is added when the stage is applied. The issue is closed
+	// instead for the last stage, which has no label.
+	label  string
+	notify string
+	after  time.Duration
+}
+
+// lifecyclePlan is what the munger should do to an issue right now.
+type lifecyclePlan struct {
+	// reset removes all lifecycle labels because of fresh activity
+	reset bool
+	// notify is posted to warn that the next stage is coming
+	notify *c.Notification
+	// apply is the stage to move to, nil if nothing is due
+	apply *lifecycleStage
+}
+
+// LifecycleMunger marks issues and PRs without human activity as
+// lifecycle/stale, then lifecycle/rotten, and finally closes them. A
+// notification is posted `warning` before each step, and any human
+// activity (such as a `/remove-lifecycle` command) resets the lifecycle.
+type LifecycleMunger struct {
+	staleAfter  time.Duration
+	rottenAfter time.Duration
+	closeAfter  time.Duration
+	warning     time.Duration
+	stages      []lifecycleStage
+}
+
+var _ Munger = &LifecycleMunger{}
+
+func init() {
+	l := &LifecycleMunger{}
+	RegisterMungerOrDie(l)
+	RegisterIssueStaleComments(l)
+}
+
+// Name is the name usable in --pr-mungers
+func (l *LifecycleMunger) Name() string { return "lifecycle" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (l *LifecycleMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (l *LifecycleMunger) Initialize(config *mgh.Config, features *features.Features) error {
+	if l.warning >= l.staleAfter || l.warning >= l.rottenAfter || l.warning >= l.closeAfter {
+		return fmt.Errorf("--lifecycle-warning (%v) must be shorter than each lifecycle stage", l.warning)
+	}
+	l.stages = []lifecycleStage{
+		{label: lifecycleStaleLabel, notify: lifecycleStaleNotifyName, after: l.staleAfter},
+		{label: lifecycleRottenLabel, notify: lifecycleRottenNotifyName, after: l.rottenAfter},
+		{notify: lifecycleCloseNotifyName, after: l.closeAfter},
+	}
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (l *LifecycleMunger) EachLoop() error { return nil }
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (l *LifecycleMunger) AddFlags(cmd *cobra.Command, config *mgh.Config) {
+	cmd.Flags().DurationVar(&l.staleAfter, "lifecycle-stale-after", 90*day, "Inactivity after which an issue is marked "+lifecycleStaleLabel)
+	cmd.Flags().DurationVar(&l.rottenAfter, "lifecycle-rotten-after", 30*day, "Time after "+lifecycleStaleLabel+" before an issue is marked "+lifecycleRottenLabel)
+	cmd.Flags().DurationVar(&l.closeAfter, "lifecycle-close-after", 30*day, "Time after "+lifecycleRottenLabel+" before an issue is closed")
+	cmd.Flags().DurationVar(&l.warning, "lifecycle-warning", 7*day, "How long before each lifecycle step a notification is posted")
+}
+
+// lastLifecycleActivity returns the date of the last human activity on the
+// issue. Adding a lifecycle label by hand is not activity, it is a shortcut.
+// Commands such as `/remove-lifecycle stale` are human comments too.
+func lastLifecycleActivity(issue *github.Issue, events []*github.IssueEvent, comments []*github.IssueComment) time.Time {
+	return mungerutil.LastHumanActivity(issue, events, comments,
+		e.And([]e.Matcher{e.AddLabel{}, e.LabelPrefix(lifecycleLabelPrefix)}))
+}
+
+// plan decides what to do with the issue at time `now`
+func (l *LifecycleMunger) plan(issue *github.Issue, events []*github.IssueEvent, comments []*github.IssueComment, now time.Time) lifecyclePlan {
+	lastActivity := lastLifecycleActivity(issue, events, comments)
+	labels := sets.NewString(mgh.GetLabelsWithPrefix(issue.Labels, lifecycleLabelPrefix)...)
+
+	// Find the current stage, and when it was entered.
+	current := -1
+	since := lastActivity
+	for i, stage := range l.stages {
+		if stage.label == "" || !labels.Has(stage.label) {
+			continue
+		}
+		labeled := e.LastEvent(events, e.And([]e.Matcher{e.AddLabel{}, e.LabelName(stage.label)}), nil)
+		if labeled == nil || labeled.Before(lastActivity) {
+			return lifecyclePlan{reset: true}
+		}
+		current = i
+		since = *labeled
+	}
+	if current+1 >= len(l.stages) {
+		return lifecyclePlan{}
+	}
+	next := &l.stages[current+1]
+	due := since.Add(next.after)
+
+	notifs := c.FilterComments(comments, c.And([]c.Matcher{
+		c.MungerNotificationName(next.notify),
+		c.CreatedAfter(since),
+	}))
+	if notifs.Empty() {
+		if now.Before(due.Add(-l.warning)) {
+			return lifecyclePlan{}
+		}
+		return lifecyclePlan{notify: l.notification(issue, next, lastActivity, now)}
+	}
+	// Always give the full warning, even if the notification came late.
+	if warned := notifs.GetLast().CreatedAt.Add(l.warning); warned.After(due) {
+		due = warned
+	}
+	if now.Before(due) {
+		return lifecyclePlan{}
+	}
+	return lifecyclePlan{apply: next}
+}
+
+func (l *LifecycleMunger) notification(issue *github.Issue, next *lifecycleStage, lastActivity, now time.Time) *c.Notification {
+	what := "be closed"
+	if next.label != "" {
+		what = "be marked " + next.label
+	}
+	return &c.Notification{
+		Name:      next.notify,
+		Arguments: mungerutil.GetIssueUsers(issue).AllUsers().Mention().Join(),
+		Context: fmt.Sprintf(
+			"This has not been active in %s. It will %s in %s unless there is new activity, or someone comments `/%s`.",
+			durationToDays(now.Sub(lastActivity)),
+			what,
+			durationToDays(l.warning),
+			lifecycleRemoveCommand,
+		),
+	}
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (l *LifecycleMunger) Munge(obj *mgh.MungeObject) {
+	if obj.HasLabel(lifecycleFrozenLabel) || obj.HasLabel(keepOpenLabel) {
+		return
+	}
+
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	plan := l.plan(obj.Issue, events, comments, time.Now())
+	switch {
+	case plan.reset:
+		for _, stage := range l.stages {
+			if stage.label != "" && obj.HasLabel(stage.label) {
+				obj.RemoveLabel(stage.label)
+			}
+		}
+	case plan.notify != nil:
+		plan.notify.Post(obj)
+	case plan.apply != nil && plan.apply.label == "":
+		obj.CloseIssuef("Closing as there has been no activity since it was marked %s. Reopen it if you would like to keep working on it.", lifecycleRottenLabel)
+	case plan.apply != nil:
+		for _, stage := range l.stages {
+			if stage.label != "" && stage.label != plan.apply.label && obj.HasLabel(stage.label) {
+				obj.RemoveLabel(stage.label)
+			}
+		}
+		obj.AddLabel(plan.apply.label)
+	}
+}
+
+// staleLifecycleNotifications returns the notifications older than the
+// last human activity, including events such as removing a label.
+func staleLifecycleNotifications(issue *github.Issue, events []*github.IssueEvent, comments []*github.IssueComment) []*github.IssueComment {
+	last := lastLifecycleActivity(issue, events, comments)
+	return c.FilterComments(comments, c.And([]c.Matcher{
+		c.Or([]c.Matcher{
+			c.MungerNotificationName(lifecycleStaleNotifyName),
+			c.MungerNotificationName(lifecycleRottenNotifyName),
+			c.MungerNotificationName(lifecycleCloseNotifyName),
+		}),
+		c.CreatedBefore(last),
+	}))
+}
+
+// StaleComments returns a slice of stale comments
+func (l *LifecycleMunger) StaleComments(obj *mgh.MungeObject, comments []*github.IssueComment) []*github.IssueComment {
+	// Notifications are obsolete once a human has been active again
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return nil
+	}
+	return staleLifecycleNotifications(obj.Issue, events, comments)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+func lifecycleEvent(event, label, actor string, at time.Time) *github.IssueEvent {
+	return &github.IssueEvent{
+		Event:     &event,
+		Label:     &github.Label{Name: &label},
+		Actor:     &github.User{Login: &actor},
+		CreatedAt: &at,
+	}
+}
+
+func TestLifecyclePlan(t *testing.T) {
+	created := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return created.Add(time.Duration(days) * day) }
+
+	l := &LifecycleMunger{staleAfter: 90 * day, rottenAfter: 30 * day, closeAfter: 30 * day, warning: 7 * day}
+	if err := l.Initialize(nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		labels   []string
+		events   []*github.IssueEvent
+		comments []*github.IssueComment
+		now      time.Time
+		reset    bool
+		notify   string
+		apply    string
+		close    bool
+	}{
+		{
+			name: "Active issue",
+			now:  at(10),
+		},
+		{
+			name:   "Warn before stale",
+			now:    at(84),
+			notify: lifecycleStaleNotifyName,
+		},
+		{
+			name:     "Human comment delays the warning",
+			comments: []*github.IssueComment{github_test.Comment(1, "user", at(50), "ping")},
+			now:      at(84),
+		},
+		{
+			name:     "Bot comment is not activity",
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-bot", at(50), "test failed")},
+			now:      at(84),
+			notify:   lifecycleStaleNotifyName,
+		},
+		{
+			name:     "Wait for the warning",
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", at(84), "[LIFECYCLE-STALE]")},
+			now:      at(88),
+		},
+		{
+			name:     "Mark stale",
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", at(84), "[LIFECYCLE-STALE]")},
+			now:      at(91),
+			apply:    lifecycleStaleLabel,
+		},
+		{
+			name:     "Late warning is still given in full",
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", at(100), "[LIFECYCLE-STALE]")},
+			now:      at(105),
+		},
+		{
+			name:   "Warn before rotten",
+			labels: []string{lifecycleStaleLabel},
+			events: []*github.IssueEvent{lifecycleEvent("labeled", lifecycleStaleLabel, "k8s-merge-robot", at(91))},
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "k8s-merge-robot", at(84), "[LIFECYCLE-STALE]"),
+			},
+			now:    at(115),
+			notify: lifecycleRottenNotifyName,
+		},
+		{
+			name:   "Mark rotten",
+			labels: []string{lifecycleStaleLabel},
+			events: []*github.IssueEvent{lifecycleEvent("labeled", lifecycleStaleLabel, "k8s-merge-robot", at(91))},
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "k8s-merge-robot", at(84), "[LIFECYCLE-STALE]"),
+				github_test.Comment(2, "k8s-merge-robot", at(115), "[LIFECYCLE-ROTTEN]"),
+			},
+			now:   at(122),
+			apply: lifecycleRottenLabel,
+		},
+		{
+			name:   "Close rotten",
+			labels: []string{lifecycleRottenLabel},
+			events: []*github.IssueEvent{
+				lifecycleEvent("labeled", lifecycleStaleLabel, "k8s-merge-robot", at(91)),
+				lifecycleEvent("unlabeled", lifecycleStaleLabel, "k8s-merge-robot", at(122)),
+				lifecycleEvent("labeled", lifecycleRottenLabel, "k8s-merge-robot", at(122)),
+			},
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "k8s-merge-robot", at(152), "[LIFECYCLE-CLOSE]"),
+			},
+			now:   at(160),
+			close: true,
+		},
+		{
+			name:     "Remove lifecycle command resets",
+			labels:   []string{lifecycleStaleLabel},
+			events:   []*github.IssueEvent{lifecycleEvent("labeled", lifecycleStaleLabel, "k8s-merge-robot", at(91))},
+			comments: []*github.IssueComment{github_test.Comment(1, "user", at(95), "/remove-lifecycle stale")},
+			now:      at(96),
+			reset:    true,
+		},
+		{
+			name:   "Human labeled stale by hand",
+			labels: []string{lifecycleStaleLabel},
+			events: []*github.IssueEvent{lifecycleEvent("labeled", lifecycleStaleLabel, "user", at(10))},
+			now:    at(11),
+		},
+		{
+			name:   "Human removed the stale label",
+			events: []*github.IssueEvent{lifecycleEvent("unlabeled", lifecycleStaleLabel, "user", at(95))},
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "k8s-merge-robot", at(84), "[LIFECYCLE-STALE]"),
+			},
+			now: at(96),
+		},
+	}
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, test.labels, false)
+		issue.CreatedAt = &created
+		plan := l.plan(issue, test.events, test.comments, test.now)
+		if plan.reset != test.reset {
+			t.Errorf("%s: expected reset %v, got %v", test.name, test.reset, plan.reset)
+		}
+		notify := ""
+		if plan.notify != nil {
+			notify = plan.notify.Name
+		}
+		if notify != test.notify {
+			t.Errorf("%s: expected notification %q, got %q", test.name, test.notify, notify)
+		}
+		wantApply := test.apply != "" || test.close
+		if (plan.apply != nil) != wantApply {
+			t.Errorf("%s: expected apply %v, got %v", test.name, wantApply, plan.apply)
+		} else if plan.apply != nil && plan.apply.label != test.apply {
+			t.Errorf("%s: expected stage %q, got %q", test.name, test.apply, plan.apply.label)
+		}
+	}
+}
+
+func TestStaleLifecycleNotifications(t *testing.T) {
+	created := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return created.Add(time.Duration(days) * day) }
+	notification := github_test.Comment(1, "k8s-merge-robot", at(84), "[LIFECYCLE-STALE]")
+
+	tests := []struct {
+		name     string
+		events   []*github.IssueEvent
+		comments []*github.IssueComment
+		stale    bool
+	}{
+		{
+			name:     "No activity since the notification",
+			comments: []*github.IssueComment{notification},
+		},
+		{
+			name: "Human comment",
+			comments: []*github.IssueComment{
+				notification,
+				github_test.Comment(2, "user", at(90), "Still happening"),
+			},
+			stale: true,
+		},
+		{
+			name:     "Human removed the stale label",
+			events:   []*github.IssueEvent{lifecycleEvent("unlabeled", lifecycleStaleLabel, "user", at(95))},
+			comments: []*github.IssueComment{notification},
+			stale:    true,
+		},
+		{
+			name:     "Bot labeled the issue",
+			events:   []*github.IssueEvent{lifecycleEvent("labeled", lifecycleStaleLabel, "k8s-merge-robot", at(91))},
+			comments: []*github.IssueComment{notification},
+		},
+	}
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, nil, false)
+		issue.CreatedAt = &created
+		stale := staleLifecycleNotifications(issue, test.events, test.comments)
+		if (len(stale) == 1) != test.stale {
+			t.Errorf("%s: expected stale %v, got %v", test.name, test.stale, stale)
+		}
+	}
+}
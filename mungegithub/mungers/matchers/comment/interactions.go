@@ -60,6 +60,18 @@ func (c CommandArguments) Match(comment *github.IssueComment) bool {
 	return (*regexp.Regexp)(&c).MatchString(command.Arguments)
 }
 
+// NotificationArguments identifies notifications by arguments (with regex)
+type NotificationArguments regexp.Regexp
+
+// Match if the notification arguments match the regexp
+func (n NotificationArguments) Match(comment *github.IssueComment) bool {
+	notif := ParseNotification(comment)
+	if notif == nil {
+		return false
+	}
+	return (*regexp.Regexp)(&n).MatchString(notif.Arguments)
+}
+
 // MungeBotAuthor creates a matcher to find mungebot comments
 func MungeBotAuthor() Matcher {
 	return AuthorLogin("k8s-merge-robot")
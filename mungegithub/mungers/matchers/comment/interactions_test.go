@@ -79,3 +79,18 @@ func TestCommandArgmuents(t *testing.T) {
 		t.Error("Shouldn't match command name")
 	}
 }
+
+func TestNotificationArguments(t *testing.T) {
+	if NotificationArguments(*regexp.MustCompile(".*")).Match(&github.IssueComment{}) {
+		t.Error("Shouldn't match nil body")
+	}
+	if NotificationArguments(*regexp.MustCompile(".*")).Match(makeCommentWithBody("/command argument")) {
+		t.Error("Shouldn't match non-notification")
+	}
+	if !NotificationArguments(*regexp.MustCompile("^@user$")).Match(makeCommentWithBody("[PING] @user\n\nSome context")) {
+		t.Error("Should match arguments of the notification")
+	}
+	if NotificationArguments(*regexp.MustCompile("PING")).Match(makeCommentWithBody("[PING] @user")) {
+		t.Error("Shouldn't match notification name")
+	}
+}
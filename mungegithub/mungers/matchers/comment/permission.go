@@ -0,0 +1,53 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comment
+
+import (
+	"strings"
+
+	"github.com/google/go-github/github"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+// Permission matches comments made by users having a given permission
+// (such as "push" or "admin") on the repository.
+type Permission struct {
+	logins sets.String
+}
+
+// NewPermission creates a Permission matcher from the list of
+// collaborators (and their permissions) of the repository.
+func NewPermission(users []*github.User, permission string) Permission {
+	logins := sets.NewString()
+	for _, user := range users {
+		if user == nil || user.Login == nil || user.Permissions == nil {
+			continue
+		}
+		if (*user.Permissions)[permission] {
+			logins.Insert(strings.ToLower(*user.Login))
+		}
+	}
+	return Permission{logins: logins}
+}
+
+// Match if the author of the comment has the permission (ignoring case)
+func (p Permission) Match(comment *github.IssueComment) bool {
+	if !(ValidAuthor{}).Match(comment) {
+		return false
+	}
+	return p.logins.Has(strings.ToLower(*comment.User.Login))
+}
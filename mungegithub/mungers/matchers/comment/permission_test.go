@@ -0,0 +1,48 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comment
+
+import (
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+func makeCollaborator(login string, permissions map[string]bool) *github.User {
+	return &github.User{Login: &login, Permissions: &permissions}
+}
+
+func TestPermission(t *testing.T) {
+	permission := NewPermission([]*github.User{
+		makeCollaborator("Pusher", map[string]bool{"pull": true, "push": true}),
+		makeCollaborator("puller", map[string]bool{"pull": true}),
+		{},
+	}, "push")
+
+	if permission.Match(&github.IssueComment{}) {
+		t.Error("Shouldn't match comment without author")
+	}
+	if !permission.Match(makeComment("/lgtm", "pusher", 0)) {
+		t.Error("Should match user with permission, ignoring case")
+	}
+	if permission.Match(makeComment("/lgtm", "puller", 0)) {
+		t.Error("Shouldn't match user without permission")
+	}
+	if permission.Match(makeComment("/lgtm", "stranger", 0)) {
+		t.Error("Shouldn't match user who is not a collaborator")
+	}
+}
@@ -61,6 +61,39 @@ func (r RemoveLabel) Match(event *github.IssueEvent) bool {
 	return *event.Event == "unlabeled"
 }
 
+// AddAssignee searches for "assigned" event.
+type AddAssignee struct{}
+
+// Match if the event is of type "assigned"
+func (a AddAssignee) Match(event *github.IssueEvent) bool {
+	if event == nil || event.Event == nil {
+		return false
+	}
+	return *event.Event == "assigned"
+}
+
+// RemoveAssignee searches for "unassigned" event.
+type RemoveAssignee struct{}
+
+// Match if the event is of type "unassigned"
+func (r RemoveAssignee) Match(event *github.IssueEvent) bool {
+	if event == nil || event.Event == nil {
+		return false
+	}
+	return *event.Event == "unassigned"
+}
+
+// Assignee searches for event about a specific assignee
+type Assignee string
+
+// Match if the event is about the specified assignee
+func (a Assignee) Match(event *github.IssueEvent) bool {
+	if event == nil || event.Assignee == nil || event.Assignee.Login == nil {
+		return false
+	}
+	return strings.ToLower(*event.Assignee.Login) == strings.ToLower(string(a))
+}
+
 // LabelPrefix searches for event whose label starts with the string
 type LabelPrefix string
 
@@ -108,3 +108,34 @@ func TestActor(t *testing.T) {
 		t.Error("Should match actor with similar name, but different case")
 	}
 }
+
+func TestAssignee(t *testing.T) {
+	if Assignee("user").Match(nil) {
+		t.Error("Shouldn't match nil event")
+	}
+	if Assignee("user").Match(&github.IssueEvent{Assignee: &github.User{}}) {
+		t.Error("Shouldn't match nil Login")
+	}
+	login := "User"
+	event := "assigned"
+	assigned := &github.IssueEvent{Event: &event, Assignee: &github.User{Login: &login}}
+	if Assignee("other").Match(assigned) {
+		t.Error("Shouldn't match a different assignee")
+	}
+	if !Assignee("user").Match(assigned) {
+		t.Error("Should match assignee with different case")
+	}
+	if !(AddAssignee{}).Match(assigned) {
+		t.Error("Should match assigned event")
+	}
+	if (AddAssignee{}).Match(makeEventWithActor("user")) {
+		t.Error("Shouldn't match event without type")
+	}
+	if (RemoveAssignee{}).Match(assigned) {
+		t.Error("Shouldn't match assigned event")
+	}
+	unassigned := "unassigned"
+	if !(RemoveAssignee{}).Match(&github.IssueEvent{Event: &unassigned}) {
+		t.Error("Should match unassigned event")
+	}
+}
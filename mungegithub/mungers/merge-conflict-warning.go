@@ -0,0 +1,203 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const mergeConflictNotifyName = "MERGE-CONFLICT"
+
+// prMergeability is what we know about the mergeability of a PR
+type prMergeability struct {
+	// base and head are the commits of the base branch and of the PR
+	// when the mergeability was checked
+	base  string
+	head  string
+	clean bool
+}
+
+// conflictTracker remembers the mergeability of the PRs, and tells
+// when a PR which was clean starts conflicting after its base advanced.
+// Only the PRs seen during the previous and current loops are remembered.
+type conflictTracker struct {
+	// previous is what was known at the end of the previous loop
+	previous map[int]*prMergeability
+	// current are the PRs seen during the current loop
+	current map[int]*prMergeability
+}
+
+func newConflictTracker() *conflictTracker {
+	return &conflictTracker{
+		previous: map[int]*prMergeability{},
+		current:  map[int]*prMergeability{},
+	}
+}
+
+// nextLoop forgets the PRs which were not seen during the loop
+func (t *conflictTracker) nextLoop() {
+	t.previous = t.current
+	t.current = map[int]*prMergeability{}
+}
+
+// get returns what is known about PR `num`
+func (t *conflictTracker) get(num int) (*prMergeability, bool) {
+	if state, ok := t.current[num]; ok {
+		return state, true
+	}
+	state, ok := t.previous[num]
+	return state, ok
+}
+
+// update checks the mergeability of PR `num` if its base branch (now at
+// `base`) or the PR itself (now at `head`) changed. It returns true if the
+// PR went from clean to conflicting because of the base branch.
+func (t *conflictTracker) update(num int, base, head string, mergeable func() (bool, error)) (bool, error) {
+	previous, known := t.get(num)
+	if known && previous.base == base && previous.head == head {
+		t.current[num] = previous
+		return false, nil
+	}
+	clean, err := mergeable()
+	if err != nil {
+		if known {
+			t.current[num] = previous
+		}
+		return false, err
+	}
+	t.current[num] = &prMergeability{base: base, head: head, clean: clean}
+	return known && previous.clean && !clean && previous.base != base, nil
+}
+
+// MergeConflictWarning re-checks the mergeability of approved PRs each time
+// their base branch advances, and warns the authors as soon as a PR which
+// was mergeable starts conflicting, rather than when it reaches the
+// merge queue. The mergeability is only remembered in memory: after a
+// restart, PRs need to be seen clean once before they can be warned about,
+// so none is warned about during the first loop.
+type MergeConflictWarning struct {
+	features *features.Features
+	tracker  *conflictTracker
+	// heads caches the commit of the base branches for the current loop
+	heads map[string]string
+}
+
+func init() {
+	m := &MergeConflictWarning{}
+	RegisterMungerOrDie(m)
+	RegisterStaleComments(m)
+}
+
+// Name is the name usable in --pr-mungers
+func (m *MergeConflictWarning) Name() string { return "merge-conflict-warning" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (m *MergeConflictWarning) RequiredFeatures() []string {
+	return []string{features.RepoFeatureName}
+}
+
+// Initialize will initialize the munger
+func (m *MergeConflictWarning) Initialize(config *github.Config, features *features.Features) error {
+	m.features = features
+	m.tracker = newConflictTracker()
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (m *MergeConflictWarning) EachLoop() error {
+	// The repository was just updated, base branches may have advanced
+	m.heads = map[string]string{}
+	m.tracker.nextLoop()
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (m *MergeConflictWarning) AddFlags(cmd *cobra.Command, config *github.Config) {}
+
+// head returns the current commit of the base `branch`
+func (m *MergeConflictWarning) head(branch string) (string, error) {
+	if sha, ok := m.heads[branch]; ok {
+		return sha, nil
+	}
+	out, err := m.features.Repos.GitCommand([]string{"rev-parse", "origin/" + branch})
+	if err != nil {
+		return "", fmt.Errorf("unable to find the head of %s: %s", branch, out)
+	}
+	sha := strings.TrimSpace(string(out))
+	m.heads[branch] = sha
+	return sha, nil
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (m *MergeConflictWarning) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() || obj.Issue.User == nil || obj.Issue.User.Login == nil {
+		return
+	}
+	if !(obj.HasLabel(lgtmLabel) || obj.HasLabel(approvedLabel)) {
+		return
+	}
+	branch := obj.Branch()
+	if branch == "" {
+		return
+	}
+	base, err := m.head(branch)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	pr, err := obj.GetPR()
+	if err != nil || pr.Head == nil || pr.Head.SHA == nil {
+		return
+	}
+
+	conflicting, err := m.tracker.update(*obj.Issue.Number, base, *pr.Head.SHA, obj.IsMergeable)
+	if err != nil {
+		glog.V(2).Infof("Skipping %d - problem determining mergeable: %v", *obj.Issue.Number, err)
+		return
+	}
+	if !conflicting {
+		return
+	}
+
+	notif := c.Notification{
+		Name:      mergeConflictNotifyName,
+		Arguments: "@" + *obj.Issue.User.Login,
+		Context: fmt.Sprintf(
+			"This PR was approved and could be merged, but `%s` advanced to %s and it now has conflicts. Please rebase it before it reaches the merge queue.",
+			branch, base),
+	}
+	notif.Post(obj)
+}
+
+// StaleComments returns a slice of stale comments
+func (m *MergeConflictWarning) StaleComments(obj *github.MungeObject, comments []*githubapi.IssueComment) []*githubapi.IssueComment {
+	// Warnings are obsolete once the PR is mergeable again
+	if state, ok := m.tracker.get(*obj.Issue.Number); !ok || !state.clean {
+		return nil
+	}
+	return c.FilterComments(comments, c.MungerNotificationName(mergeConflictNotifyName))
+}
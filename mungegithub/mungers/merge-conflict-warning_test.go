@@ -0,0 +1,130 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConflictTracker(t *testing.T) {
+	type check struct {
+		base, head string
+		mergeable  bool
+		err        error
+		checked    bool
+		warn       bool
+	}
+	tests := []struct {
+		name   string
+		checks []check
+	}{
+		{
+			name: "Base advanced and conflicts",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: true, checked: true},
+				{base: "b2", head: "h1", mergeable: false, checked: true, warn: true},
+			},
+		},
+		{
+			name: "Base didn't advance",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: true, checked: true},
+				{base: "b1", head: "h1", mergeable: false},
+			},
+		},
+		{
+			name: "Unknown state is not clean",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: false, checked: true},
+				{base: "b2", head: "h1", mergeable: false, checked: true},
+			},
+		},
+		{
+			name: "Warn once",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: true, checked: true},
+				{base: "b2", head: "h1", mergeable: false, checked: true, warn: true},
+				{base: "b3", head: "h1", mergeable: false, checked: true},
+			},
+		},
+		{
+			name: "Author push is not the base advancing",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: true, checked: true},
+				{base: "b1", head: "h2", mergeable: false, checked: true},
+			},
+		},
+		{
+			name: "Rebased then conflicts again",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: true, checked: true},
+				{base: "b2", head: "h1", mergeable: false, checked: true, warn: true},
+				{base: "b2", head: "h2", mergeable: true, checked: true},
+				{base: "b3", head: "h2", mergeable: false, checked: true, warn: true},
+			},
+		},
+		{
+			name: "Errors are retried",
+			checks: []check{
+				{base: "b1", head: "h1", mergeable: true, checked: true},
+				{base: "b2", head: "h1", err: fmt.Errorf("no mergeability"), checked: true},
+				{base: "b2", head: "h1", mergeable: false, checked: true, warn: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tracker := newConflictTracker()
+		for i, check := range test.checks {
+			checked := false
+			warn, err := tracker.update(1, check.base, check.head, func() (bool, error) {
+				checked = true
+				return check.mergeable, check.err
+			})
+			if (err != nil) != (check.err != nil) {
+				t.Errorf("%s (%d): unexpected error %v", test.name, i, err)
+			}
+			if checked != check.checked {
+				t.Errorf("%s (%d): expected checked %v, got %v", test.name, i, check.checked, checked)
+			}
+			if warn != check.warn {
+				t.Errorf("%s (%d): expected warning %v, got %v", test.name, i, check.warn, warn)
+			}
+		}
+	}
+}
+
+func TestConflictTrackerForgetsUnseenPRs(t *testing.T) {
+	tracker := newConflictTracker()
+	clean := func() (bool, error) { return true, nil }
+	conflicting := func() (bool, error) { return false, nil }
+
+	tracker.update(1, "b1", "h1", clean)
+	tracker.update(2, "b1", "h1", clean)
+	tracker.nextLoop()
+	// PR 2 was closed
+	tracker.update(1, "b1", "h1", clean)
+	tracker.nextLoop()
+
+	if _, ok := tracker.get(2); ok {
+		t.Errorf("Expected PR 2 to be forgotten")
+	}
+	if warn, _ := tracker.update(1, "b2", "h1", conflicting); !warn {
+		t.Errorf("Expected PR 1 to be remembered across loops")
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungerutil
+
+import (
+	"time"
+
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+
+	"github.com/google/go-github/github"
+)
+
+// LastHumanActivity returns the date of the last comment or event from a
+// human on the issue, or its creation date if there is none. Events matched
+// by `ignored`, if not nil, are not considered as activity.
+func LastHumanActivity(issue *github.Issue, events []*github.IssueEvent, comments []*github.IssueComment, ignored e.Matcher) time.Time {
+	last := c.LastComment(comments, c.HumanActor(), issue.CreatedAt)
+	if last == nil {
+		last = &time.Time{}
+	}
+	activity := e.HumanActor()
+	if ignored != nil {
+		activity = e.And([]e.Matcher{activity, e.Not{Matcher: ignored}})
+	}
+	if event := e.LastEvent(events, activity, last); event.After(*last) {
+		last = event
+	}
+	return *last
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungerutil
+
+import (
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+
+	"github.com/google/go-github/github"
+)
+
+func activityEvent(event, label, actor string, at int64) *github.IssueEvent {
+	date := time.Unix(at, 0)
+	return &github.IssueEvent{
+		Event:     &event,
+		Label:     &github.Label{Name: &label},
+		Actor:     &github.User{Login: &actor},
+		CreatedAt: &date,
+	}
+}
+
+func TestLastHumanActivity(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []*github.IssueEvent
+		comments []*github.IssueComment
+		ignored  e.Matcher
+		expected int64
+	}{
+		{
+			name:     "No activity since creation",
+			expected: 10,
+		},
+		{
+			name:     "Human comment",
+			comments: []*github.IssueComment{github_test.Comment(1, "user", time.Unix(20, 0), "ping")},
+			expected: 20,
+		},
+		{
+			name:     "Bot comment",
+			comments: []*github.IssueComment{github_test.Comment(1, BotName, time.Unix(20, 0), "[PING]")},
+			expected: 10,
+		},
+		{
+			name:     "Human event after the comments",
+			events:   []*github.IssueEvent{activityEvent("unlabeled", "lifecycle/stale", "user", 30)},
+			comments: []*github.IssueComment{github_test.Comment(1, "user", time.Unix(20, 0), "ping")},
+			expected: 30,
+		},
+		{
+			name:     "Bot event",
+			events:   []*github.IssueEvent{activityEvent("labeled", "lifecycle/stale", BotName, 30)},
+			expected: 10,
+		},
+		{
+			name:     "Ignored event",
+			events:   []*github.IssueEvent{activityEvent("labeled", "lifecycle/stale", "user", 30)},
+			ignored:  e.And([]e.Matcher{e.AddLabel{}, e.LabelPrefix("lifecycle/")}),
+			expected: 10,
+		},
+	}
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, nil, false)
+		created := time.Unix(10, 0)
+		issue.CreatedAt = &created
+		last := LastHumanActivity(issue, test.events, test.comments, test.ignored)
+		if !last.Equal(time.Unix(test.expected, 0)) {
+			t.Errorf("%s: expected %v, got %v", test.name, time.Unix(test.expected, 0), last)
+		}
+	}
+}
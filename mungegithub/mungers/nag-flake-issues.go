@@ -54,7 +54,7 @@ var _ Munger = &NagFlakeIssues{}
 func init() {
 	n := &NagFlakeIssues{}
 	RegisterMungerOrDie(n)
-	RegisterStaleComments(n)
+	RegisterIssueStaleComments(n)
 }
 
 // Name is the name usable in --pr-mungers
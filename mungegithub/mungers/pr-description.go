@@ -0,0 +1,166 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	needsDescriptionLabel      = "needs-description"
+	needsDescriptionNotifyName = "NEEDS-DESCRIPTION"
+)
+
+// templateCommentRE matches the hints left as HTML comments by the PR template
+var templateCommentRE = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// PRDescriptionMunger checks that PR descriptions follow the rules: the
+// template sections are present, an issue is linked with "Fixes #" and
+// the description is long enough. Until they do, the PR is labeled
+// needs-description and a single comment lists what is missing.
+type PRDescriptionMunger struct {
+	Sections      []string
+	RequireIssue  bool
+	MinimumLength int
+}
+
+func init() {
+	p := &PRDescriptionMunger{}
+	RegisterMungerOrDie(p)
+	RegisterStaleComments(p)
+}
+
+// Name is the name usable in --pr-mungers
+func (p *PRDescriptionMunger) Name() string { return "pr-description" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (p *PRDescriptionMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (p *PRDescriptionMunger) Initialize(config *github.Config, features *features.Features) error {
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (p *PRDescriptionMunger) EachLoop() error { return nil }
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (p *PRDescriptionMunger) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().StringSliceVar(&p.Sections, "description-sections", []string{}, "Sections of the PR template (such as 'Release note') that the PR description must contain")
+	cmd.Flags().BoolVar(&p.RequireIssue, "description-require-issue", false, "If true, the PR description must link an issue with 'Fixes #<issue>'")
+	cmd.Flags().IntVar(&p.MinimumLength, "description-min-length", 30, "Minimum length of the PR description, without the template hints and section titles")
+}
+
+// problems returns what is wrong with the PR description `body`. `fixes`
+// are the issues linked from the description. The template hints are
+// ignored, as they may mention the sections too.
+func (p *PRDescriptionMunger) problems(body string, fixes []int) []string {
+	problems := []string{}
+	body = templateCommentRE.ReplaceAllString(body, "")
+	lower := strings.ToLower(body)
+	for _, section := range p.Sections {
+		if !strings.Contains(lower, strings.ToLower(section)) {
+			problems = append(problems, fmt.Sprintf("The %q section of the template is missing.", section))
+		}
+	}
+	if p.RequireIssue && len(fixes) == 0 {
+		problems = append(problems, "No issue is linked, please add `Fixes #<issue number>`.")
+	}
+	// The section titles alone don't describe anything
+	text := body
+	for _, section := range p.Sections {
+		text = regexp.MustCompile(`(?i)`+regexp.QuoteMeta(section)).ReplaceAllString(text, "")
+	}
+	text = strings.TrimSpace(text)
+	if len(text) < p.MinimumLength {
+		problems = append(problems, fmt.Sprintf("The description is too short, please explain what this PR does and why (at least %d characters).", p.MinimumLength))
+	}
+	return problems
+}
+
+func descriptionNotification(problems []string) *c.Notification {
+	return &c.Notification{
+		Name: needsDescriptionNotifyName,
+		Context: "Please update the description of this PR:\n- " + strings.Join(problems, "\n- ") +
+			"\n\nThe " + needsDescriptionLabel + " label will be removed once it is fixed.",
+	}
+}
+
+func (p *PRDescriptionMunger) objProblems(obj *github.MungeObject) []string {
+	body := ""
+	if obj.Issue.Body != nil {
+		body = *obj.Issue.Body
+	}
+	return p.problems(body, obj.GetPRFixesList())
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (p *PRDescriptionMunger) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() {
+		return
+	}
+
+	problems := p.objProblems(obj)
+	if len(problems) == 0 {
+		if obj.HasLabel(needsDescriptionLabel) {
+			obj.RemoveLabel(needsDescriptionLabel)
+		}
+		return
+	}
+
+	if !obj.HasLabel(needsDescriptionLabel) {
+		obj.AddLabel(needsDescriptionLabel)
+	}
+
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	// Keep a single comment, updated when the problems change
+	notif := descriptionNotification(problems)
+	last := c.FilterComments(comments, c.MungerNotificationName(needsDescriptionNotifyName)).GetLast()
+	switch {
+	case last == nil:
+		notif.Post(obj)
+	case last.Body == nil || *last.Body != notif.String():
+		obj.EditComment(last, notif.String())
+	}
+}
+
+// StaleComments returns a slice of stale comments
+func (p *PRDescriptionMunger) StaleComments(obj *github.MungeObject, comments []*githubapi.IssueComment) []*githubapi.IssueComment {
+	notifs := c.FilterComments(comments, c.MungerNotificationName(needsDescriptionNotifyName))
+	if notifs.Empty() {
+		return nil
+	}
+	if len(p.objProblems(obj)) == 0 {
+		return notifs
+	}
+	// Only the last one is kept up to date
+	return notifs[:len(notifs)-1]
+}
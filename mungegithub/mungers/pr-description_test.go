@@ -0,0 +1,94 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPRDescriptionProblems(t *testing.T) {
+	p := &PRDescriptionMunger{
+		Sections:      []string{"**What this PR does / why we need it**", "**Release note**"},
+		RequireIssue:  true,
+		MinimumLength: 30,
+	}
+	template := "<!-- Thanks for sending a pull request! Explain **What this PR does / why we need it**, and add a **Release note**. -->\n" +
+		"**What this PR does / why we need it**:\n\n**Release note**:\n" +
+		"<!-- Write NONE in the block below if there is no **Release note**. -->\n```release-note\n```\n"
+	complete := "**What this PR does / why we need it**: it fixes the flaky test.\n\n**Release note**:\n```release-note\nNONE\n```"
+
+	tests := []struct {
+		name     string
+		body     string
+		fixes    []int
+		expected int
+	}{
+		{
+			name:     "Complete description",
+			body:     complete,
+			fixes:    []int{123},
+			expected: 0,
+		},
+		{
+			name:     "Empty description",
+			body:     "",
+			expected: 4,
+		},
+		{
+			name:     "No linked issue",
+			body:     complete,
+			expected: 1,
+		},
+		{
+			name:     "Section case doesn't matter",
+			body:     strings.ToUpper(complete),
+			fixes:    []int{123},
+			expected: 0,
+		},
+		{
+			name:     "Missing section",
+			body:     "**What this PR does / why we need it**: it fixes the flaky test of the scheduler.",
+			fixes:    []int{123},
+			expected: 1,
+		},
+		{
+			name:     "Template hints don't count",
+			body:     "<!-- Thanks for sending a pull request! Please describe it below. -->\nFix",
+			fixes:    []int{123},
+			expected: 3,
+		},
+		{
+			name:     "Sections mentioned in the hints only",
+			body:     "<!-- Add a **Release note** section. -->\n**What this PR does / why we need it**: it fixes the flaky test of the scheduler.",
+			fixes:    []int{123},
+			expected: 1,
+		},
+		{
+			name:     "Template left unedited",
+			body:     template,
+			expected: 2,
+		},
+	}
+
+	for _, test := range tests {
+		problems := p.problems(test.body, test.fixes)
+		if len(problems) != test.expected {
+			t.Errorf("%s: expected %d problems, got %v", test.name, test.expected, problems)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/sync"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// failedTestsRE matches the header of the k8s-bot failure comments
+	failedTestsRE = regexp.MustCompile(`The following tests? \*\*failed\*\*`)
+	// failedTestRowRE matches a row of the table listing the failed tests:
+	// Test name | Commit | Details | Rerun command
+	failedTestRowRE = regexp.MustCompile(`(?m)^([^|\n]+?) \| [[:xdigit:]]+ \| \[link\]\(([^)]+)\) \|`)
+)
+
+// prFailures are the failures of a test, by PR number
+type prFailures map[int]string
+
+// PRFlakeTracker reads the test failures reported by k8s-bot on PRs. A
+// test failing on several PRs is likely to be flaky rather than broken
+// by the PRs, so it gets a kind/flake issue (shared with flake-manager)
+// listing the PRs it failed on.
+type PRFlakeTracker struct {
+	MinPRs   int
+	finder   issueFinder
+	syncer   *sync.IssueSyncer
+	failures map[string]prFailures
+}
+
+func init() {
+	RegisterMungerOrDie(&PRFlakeTracker{})
+}
+
+// Name is the name usable in --pr-mungers
+func (p *PRFlakeTracker) Name() string { return "pr-flake-tracker" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (p *PRFlakeTracker) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (p *PRFlakeTracker) Initialize(config *github.Config, features *features.Features) error {
+	// TODO: don't get the mungers from the global list, they should be passed in...
+	for _, m := range GetAllMungers() {
+		if m.Name() == "issue-cacher" {
+			p.finder = m.(*IssueCacher)
+		}
+	}
+	if p.finder == nil {
+		return fmt.Errorf("issue-cacher not found")
+	}
+	p.syncer = sync.NewIssueSyncer(config, p.finder, nil)
+	p.failures = map[string]prFailures{}
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop. The failures
+// collected during the previous loop are synced with the flake issues.
+func (p *PRFlakeTracker) EachLoop() error {
+	if !p.finder.Synced() {
+		glog.V(3).Infof("issue-cache is not synced. pr-flake-tracker is skipping this run.")
+		p.failures = map[string]prFailures{}
+		return nil
+	}
+	for _, test := range flakyTests(p.failures, p.MinPRs) {
+		if err := p.syncer.Sync(&prFlakeSource{test: test, failures: p.failures[test]}); err != nil {
+			glog.Errorf("Failed to sync flake issue for %q: %v", test, err)
+		}
+	}
+	p.failures = map[string]prFailures{}
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (p *PRFlakeTracker) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().IntVar(&p.MinPRs, "pr-flake-min-prs", 3, "Number of PRs a test must fail on to be considered flaky")
+}
+
+// parseFailedTests returns the failed tests listed in a k8s-bot comment,
+// and the link to the details of each failure.
+func parseFailedTests(body string) map[string]string {
+	tests := map[string]string{}
+	if !failedTestsRE.MatchString(body) {
+		return tests
+	}
+	for _, match := range failedTestRowRE.FindAllStringSubmatch(body, -1) {
+		tests[strings.TrimSpace(match[1])] = match[2]
+	}
+	return tests
+}
+
+// flakyTests returns the tests which failed on at least `minPRs` PRs
+func flakyTests(failures map[string]prFailures, minPRs int) []string {
+	tests := []string{}
+	for test, prs := range failures {
+		if len(prs) >= minPRs {
+			tests = append(tests, test)
+		}
+	}
+	sort.Strings(tests)
+	return tests
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (p *PRFlakeTracker) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() {
+		return
+	}
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	for _, comment := range c.FilterComments(comments, c.JenkinsBotAuthor()) {
+		if comment.Body == nil {
+			continue
+		}
+		for test, link := range parseFailedTests(*comment.Body) {
+			if p.failures[test] == nil {
+				p.failures[test] = prFailures{}
+			}
+			// Keep the most recent failure of the PR
+			p.failures[test][*obj.Issue.Number] = link
+		}
+	}
+}
+
+type prFlakeSource struct {
+	test     string
+	failures prFailures
+}
+
+func (p *prFlakeSource) prs() []int {
+	prs := []int{}
+	for pr := range p.failures {
+		prs = append(prs, pr)
+	}
+	sort.Ints(prs)
+	return prs
+}
+
+// Title implements IssueSource
+func (p *prFlakeSource) Title() string {
+	// Same title as individualFlakeSource, so that flake-manager and
+	// issue-cacher find the same issues for the test.
+	return p.test
+}
+
+// ID implements IssueSource
+func (p *prFlakeSource) ID() string {
+	// Changes whenever the test fails on another PR
+	prs := []string{}
+	for _, pr := range p.prs() {
+		prs = append(prs, fmt.Sprintf("#%d", pr))
+	}
+	return fmt.Sprintf("Failed on PRs: %s\n", strings.Join(prs, " "))
+}
+
+// Body implements IssueSource
+func (p *prFlakeSource) Body(newIssue bool) string {
+	body := fmt.Sprintf(failedStr+"%v\n\nThis test failed on %d PRs:\n\n", p.test, len(p.failures))
+	for _, pr := range p.prs() {
+		body += fmt.Sprintf("- #%d: %s\n", pr, p.failures[pr])
+	}
+	return body + "\n" + p.ID()
+}
+
+// Labels implements IssueSource
+func (p *prFlakeSource) Labels() []string {
+	return []string{"kind/flake", sync.PriorityP2.String()}
+}
+
+// Priority implements IssueSource
+func (p *prFlakeSource) Priority(obj *github.MungeObject) (sync.Priority, error) {
+	comments, err := obj.ListComments()
+	if err != nil {
+		return sync.PriorityP2, fmt.Errorf("Failed to list comment of issue: %v", err)
+	}
+	return autoPrioritize(comments, obj.Issue.CreatedAt), nil
+}
+
+var _ sync.IssueSource = &prFlakeSource{}
@@ -0,0 +1,75 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const failedTestsComment = `@user: The following tests **failed**, say ` + "`/retest`" + ` to rerun them all:
+
+Test name | Commit | Details | Rerun command
+--- | --- | --- | ---
+Jenkins GCE e2e | 3b4f1a2 | [link](https://k8s-gubernator.appspot.com/build/kubernetes-jenkins/pr-logs/pull/12/e2e/1/) | ` + "`@k8s-bot cvm gce e2e test this`" + `
+Jenkins unit/integration | 3b4f1a2 | [link](https://k8s-gubernator.appspot.com/build/kubernetes-jenkins/pr-logs/pull/12/unit/2/) | ` + "`@k8s-bot unit test this`" + `
+`
+
+func TestParseFailedTests(t *testing.T) {
+	expected := map[string]string{
+		"Jenkins GCE e2e":          "https://k8s-gubernator.appspot.com/build/kubernetes-jenkins/pr-logs/pull/12/e2e/1/",
+		"Jenkins unit/integration": "https://k8s-gubernator.appspot.com/build/kubernetes-jenkins/pr-logs/pull/12/unit/2/",
+	}
+	if tests := parseFailedTests(failedTestsComment); !reflect.DeepEqual(tests, expected) {
+		t.Errorf("Expected %v, got %v", expected, tests)
+	}
+	if tests := parseFailedTests("GCE e2e build/test **passed** for commit 3b4f1a2."); len(tests) != 0 {
+		t.Errorf("Expected no failed test, got %v", tests)
+	}
+}
+
+func TestFlakyTests(t *testing.T) {
+	failures := map[string]prFailures{
+		"Jenkins GCE e2e":          {12: "a", 13: "b", 14: "c"},
+		"Jenkins unit/integration": {12: "d"},
+		"Jenkins verification":     {12: "e", 15: "f", 16: "g", 17: "h"},
+	}
+	expected := []string{"Jenkins GCE e2e", "Jenkins verification"}
+	if tests := flakyTests(failures, 3); !reflect.DeepEqual(tests, expected) {
+		t.Errorf("Expected %v, got %v", expected, tests)
+	}
+}
+
+func TestPRFlakeSource(t *testing.T) {
+	source := &prFlakeSource{test: "Jenkins GCE e2e", failures: prFailures{14: "link14", 12: "link12"}}
+	if id := source.ID(); id != "Failed on PRs: #12 #14\n" {
+		t.Errorf("Unexpected ID %q", id)
+	}
+	body := source.Body(true)
+	if !strings.Contains(body, source.ID()) {
+		t.Errorf("Body %q must contain the ID", body)
+	}
+	if !strings.HasPrefix(body, failedStr) {
+		t.Errorf("Body %q must start with %q to be counted by autoPrioritize", body, failedStr)
+	}
+
+	source.failures[13] = "link13"
+	if id := source.ID(); id != "Failed on PRs: #12 #13 #14\n" {
+		t.Errorf("Unexpected ID %q", id)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/contrib/mungegithub/features"
+	mgh "k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	"k8s.io/contrib/mungegithub/mungers/mungerutil"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	slaBreachLabel      = "sla-breach"
+	slaBreachNotifyName = "SLA-BREACH"
+)
+
+// slaWebhookClient posts to the webhook. An unresponsive webhook must not
+// stall the munge loop.
+var slaWebhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// PrioritySLAMunger escalates issues that haven't had any human activity
+// for longer than the SLO of their priority: they are labeled sla-breach,
+// the assignees are pinged and, optionally, a webhook (such as a Slack
+// incoming webhook) is notified. The label is removed on new activity.
+type PrioritySLAMunger struct {
+	SLOs    []string
+	Webhook string
+	slos    map[string]time.Duration
+}
+
+func init() {
+	p := &PrioritySLAMunger{}
+	RegisterMungerOrDie(p)
+	RegisterIssueStaleComments(p)
+}
+
+// Name is the name usable in --pr-mungers
+func (p *PrioritySLAMunger) Name() string { return "priority-sla" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (p *PrioritySLAMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (p *PrioritySLAMunger) Initialize(config *mgh.Config, features *features.Features) error {
+	slos, err := parseSLOs(p.SLOs)
+	if err != nil {
+		return err
+	}
+	p.slos = slos
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (p *PrioritySLAMunger) EachLoop() error { return nil }
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (p *PrioritySLAMunger) AddFlags(cmd *cobra.Command, config *mgh.Config) {
+	cmd.Flags().StringSliceVar(&p.SLOs, "sla-slos", []string{"priority/P0=72h", "priority/P1=336h"}, "Maximum time without activity for each priority, as label=duration")
+	cmd.Flags().StringVar(&p.Webhook, "sla-webhook", "", "If set, URL receiving a JSON {\"text\": ...} message for each new SLA breach")
+}
+
+// parseSLOs reads the label=duration list of SLOs
+func parseSLOs(values []string) (map[string]time.Duration, error) {
+	slos := map[string]time.Duration{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid SLO %q, expected label=duration", value)
+		}
+		slo, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLO %q: %v", value, err)
+		}
+		slos[parts[0]] = slo
+	}
+	return slos, nil
+}
+
+// slo returns the SLO of the issue, the shortest one of its labels
+func (p *PrioritySLAMunger) slo(issue *github.Issue) (time.Duration, bool) {
+	var slo time.Duration
+	found := false
+	for _, label := range issue.Labels {
+		if label.Name == nil {
+			continue
+		}
+		if d, ok := p.slos[*label.Name]; ok && (!found || d < slo) {
+			slo = d
+			found = true
+		}
+	}
+	return slo, found
+}
+
+// breached tells if the issue has gone without activity for longer than its
+// SLO at time `now`, and returns the last activity.
+func (p *PrioritySLAMunger) breached(issue *github.Issue, events []*github.IssueEvent, comments []*github.IssueComment, now time.Time) (bool, time.Time) {
+	slo, ok := p.slo(issue)
+	if !ok {
+		return false, time.Time{}
+	}
+	last := mungerutil.LastHumanActivity(issue, events, comments, nil)
+	return now.Sub(last) > slo, last
+}
+
+// notifyWebhook posts the message to the external channel, if any
+func (p *PrioritySLAMunger) notifyWebhook(message string) {
+	if p.Webhook == "" {
+		return
+	}
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	resp, err := slaWebhookClient.Post(p.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		glog.Errorf("Failed to notify SLA webhook: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		response, _ := ioutil.ReadAll(resp.Body)
+		glog.Errorf("SLA webhook returned %d: %s", resp.StatusCode, response)
+	}
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (p *PrioritySLAMunger) Munge(obj *mgh.MungeObject) {
+	if obj.IsPR() {
+		return
+	}
+	slo, ok := p.slo(obj.Issue)
+	if !ok {
+		if obj.HasLabel(slaBreachLabel) {
+			obj.RemoveLabel(slaBreachLabel)
+		}
+		return
+	}
+
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	breached, last := p.breached(obj.Issue, events, comments, time.Now())
+	if !breached {
+		if obj.HasLabel(slaBreachLabel) {
+			obj.RemoveLabel(slaBreachLabel)
+		}
+		return
+	}
+
+	if !obj.HasLabel(slaBreachLabel) {
+		obj.AddLabel(slaBreachLabel)
+		url := ""
+		if obj.Issue.HTMLURL != nil {
+			url = *obj.Issue.HTMLURL
+		}
+		p.notifyWebhook(fmt.Sprintf("Issue #%d has had no activity in %s: %s", *obj.Issue.Number, durationToDays(time.Since(last)), url))
+	}
+
+	who := mungerutil.GetIssueUsers(obj.Issue).Assignees.Mention().Join()
+	if who == "" {
+		return
+	}
+	notif := c.NewPinger(slaBreachNotifyName).
+		SetDescription(fmt.Sprintf("This issue has had no activity for longer than its SLO of %s. Please give an update.", durationToDays(slo))).
+		SetTimePeriod(slo).
+		PingNotification(comments, who, &last)
+	if notif != nil {
+		notif.Post(obj)
+	}
+}
+
+// StaleComments returns a slice of stale comments
+func (p *PrioritySLAMunger) StaleComments(obj *mgh.MungeObject, comments []*github.IssueComment) []*github.IssueComment {
+	// Pings are obsolete once a human has been active again
+	return c.FilterComments(comments, c.And([]c.Matcher{
+		c.MungerNotificationName(slaBreachNotifyName),
+		c.CreatedBefore(*c.LastComment(comments, c.HumanActor(), &time.Time{})),
+	}))
+}
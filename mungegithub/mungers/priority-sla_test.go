@@ -0,0 +1,166 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+func TestParseSLOs(t *testing.T) {
+	slos, err := parseSLOs([]string{"priority/P0=72h", "priority/P1=336h"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if slos["priority/P0"] != 72*time.Hour || slos["priority/P1"] != 336*time.Hour {
+		t.Errorf("Unexpected SLOs: %v", slos)
+	}
+	for _, invalid := range []string{"priority/P0", "=72h", "priority/P0=3 days"} {
+		if _, err := parseSLOs([]string{invalid}); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestPrioritySLABreached(t *testing.T) {
+	created := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return created.Add(time.Duration(days) * day) }
+
+	p := &PrioritySLAMunger{SLOs: []string{"priority/P0=72h", "priority/P1=336h"}}
+	if err := p.Initialize(nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		labels   []string
+		events   []*github.IssueEvent
+		comments []*github.IssueComment
+		now      time.Time
+		breached bool
+	}{
+		{
+			name: "No priority",
+			now:  at(100),
+		},
+		{
+			name:   "Priority without SLO",
+			labels: []string{"priority/P3"},
+			now:    at(100),
+		},
+		{
+			name:   "Within the SLO",
+			labels: []string{"priority/P0"},
+			now:    at(2),
+		},
+		{
+			name:     "P0 untouched for 3 days",
+			labels:   []string{"priority/P0"},
+			now:      at(4),
+			breached: true,
+		},
+		{
+			name:     "P1 untouched for 4 days",
+			labels:   []string{"priority/P1"},
+			now:      at(4),
+			breached: false,
+		},
+		{
+			name:     "Shortest SLO wins",
+			labels:   []string{"priority/P1", "priority/P0"},
+			now:      at(4),
+			breached: true,
+		},
+		{
+			name:     "Human comment is activity",
+			labels:   []string{"priority/P0"},
+			comments: []*github.IssueComment{github_test.Comment(1, "user", at(3), "Looking into it")},
+			now:      at(4),
+		},
+		{
+			name:     "Bot ping is not activity",
+			labels:   []string{"priority/P0"},
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", at(3), "[SLA-BREACH] @user")},
+			now:      at(4),
+			breached: true,
+		},
+		{
+			name:   "Human event is activity",
+			labels: []string{"priority/P0"},
+			events: []*github.IssueEvent{lifecycleEvent("labeled", "priority/P0", "user", at(3))},
+			now:    at(4),
+		},
+	}
+
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, test.labels, false)
+		issue.CreatedAt = &created
+		breached, _ := p.breached(issue, test.events, test.comments, test.now)
+		if breached != test.breached {
+			t.Errorf("%s: expected breached %v, got %v", test.name, test.breached, breached)
+		}
+	}
+}
+
+func TestPrioritySLAWebhook(t *testing.T) {
+	messages := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		messages = append(messages, body["text"])
+	}))
+	defer server.Close()
+
+	p := &PrioritySLAMunger{Webhook: server.URL}
+	p.notifyWebhook("Issue #1 has had no activity in 4 days")
+	if len(messages) != 1 || messages[0] != "Issue #1 has had no activity in 4 days" {
+		t.Errorf("Unexpected messages: %q", messages)
+	}
+}
+
+func TestPrioritySLAWebhookTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := slaWebhookClient
+	defer func() { slaWebhookClient = client }()
+	slaWebhookClient = &http.Client{Timeout: 10 * time.Millisecond}
+
+	returned := make(chan struct{})
+	go func() {
+		(&PrioritySLAMunger{Webhook: server.URL}).notifyWebhook("ignored")
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected the webhook to time out")
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"k8s.io/contrib/mungegithub/features"
+	"k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+
+	"github.com/golang/glog"
+	githubapi "github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+const (
+	assignCommand   = "assign"
+	unassignCommand = "unassign"
+)
+
+// ReviewerAssigner assigns reviewers to new PRs among the OWNERS reviewers
+// of the changed files. Unlike blunderbuss, which picks a single owner at
+// random, it picks the reviewers with the fewest PRs currently assigned.
+// `/assign [@user...]` and `/unassign [@user...]` override the choice.
+type ReviewerAssigner struct {
+	Count    int
+	features *features.Features
+	// load is the number of open PRs assigned to each reviewer, counted
+	// during the previous loop and updated with our own assignments
+	load map[string]int
+	// nextLoad is being counted during the current loop
+	nextLoad map[string]int
+}
+
+func init() {
+	RegisterMungerOrDie(&ReviewerAssigner{})
+}
+
+// Name is the name usable in --pr-mungers
+func (r *ReviewerAssigner) Name() string { return "reviewer-assigner" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (r *ReviewerAssigner) RequiredFeatures() []string {
+	return []string{features.RepoFeatureName, features.AliasesFeature}
+}
+
+// Initialize will initialize the munger
+func (r *ReviewerAssigner) Initialize(config *github.Config, features *features.Features) error {
+	if r.Count < 1 {
+		return fmt.Errorf("--reviewer-count must be at least 1, got %d", r.Count)
+	}
+	r.features = features
+	r.load = map[string]int{}
+	r.nextLoad = map[string]int{}
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (r *ReviewerAssigner) EachLoop() error {
+	r.load = r.nextLoad
+	r.nextLoad = map[string]int{}
+	return nil
+}
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (r *ReviewerAssigner) AddFlags(cmd *cobra.Command, config *github.Config) {
+	cmd.Flags().IntVar(&r.Count, "reviewer-count", 2, "Number of reviewers assigned to new PRs")
+}
+
+// assigneesFromEvents replays the assignment events to find the current
+// assignees (lower case).
+func assigneesFromEvents(events []*githubapi.IssueEvent) sets.String {
+	assignees := sets.NewString()
+	for _, event := range e.FilterEvents(events, e.Or([]e.Matcher{e.AddAssignee{}, e.RemoveAssignee{}})) {
+		if event.Assignee == nil || event.Assignee.Login == nil {
+			continue
+		}
+		login := strings.ToLower(*event.Assignee.Login)
+		if (e.AddAssignee{}).Match(event) {
+			assignees.Insert(login)
+		} else {
+			assignees.Delete(login)
+		}
+	}
+	return assignees
+}
+
+type assignRequest struct {
+	assign bool
+	at     time.Time
+}
+
+// assignCommands returns what `/assign` and `/unassign` asked for each
+// user (lower case). Without argument, the commands apply to the author of
+// the comment. Users can be separated by spaces or commas.
+func assignCommands(comments []*githubapi.IssueComment) map[string]assignRequest {
+	requests := map[string]assignRequest{}
+	commands := c.FilterComments(comments, c.And([]c.Matcher{
+		c.HumanActor(),
+		c.Or([]c.Matcher{c.CommandName(assignCommand), c.CommandName(unassignCommand)}),
+	}))
+	for _, comment := range commands {
+		command := c.ParseCommand(comment)
+		logins := strings.FieldsFunc(command.Arguments, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if len(logins) == 0 {
+			logins = []string{*comment.User.Login}
+		}
+		for _, login := range logins {
+			login = strings.ToLower(strings.TrimPrefix(login, "@"))
+			if login == "" {
+				continue
+			}
+			requests[login] = assignRequest{
+				assign: strings.ToLower(command.Name) == assignCommand,
+				at:     *comment.CreatedAt,
+			}
+		}
+	}
+	return requests
+}
+
+// pendingAssignments returns the users to assign and unassign to apply
+// the commands. Commands followed by a manual change of the same user's
+// assignment are ignored.
+func pendingAssignments(requests map[string]assignRequest, events []*githubapi.IssueEvent) (assign, unassign []string) {
+	current := assigneesFromEvents(events)
+	assign, unassign = []string{}, []string{}
+	for login, request := range requests {
+		changed := e.LastEvent(events, e.And([]e.Matcher{
+			e.Or([]e.Matcher{e.AddAssignee{}, e.RemoveAssignee{}}),
+			e.Assignee(login),
+		}), nil)
+		if changed != nil && changed.After(request.at) {
+			continue
+		}
+		if request.assign && !current.Has(login) {
+			assign = append(assign, login)
+		} else if !request.assign && current.Has(login) {
+			unassign = append(unassign, login)
+		}
+	}
+	sort.Strings(assign)
+	sort.Strings(unassign)
+	return assign, unassign
+}
+
+// needsReviewers returns true if reviewers must be picked for the PR: it
+// has no assignee and was never assigned by the bot. Someone may have
+// unassigned our reviewers, they are not replaced.
+func needsReviewers(assignees sets.String, events []*githubapi.IssueEvent) bool {
+	assignedByBot := e.FilterEvents(events, e.And([]e.Matcher{e.AddAssignee{}, e.MungeBotActor()}))
+	return assignees.Len() == 0 && assignedByBot.Empty()
+}
+
+// pickReviewers returns the `count` candidates with the lowest load, by
+// name for the same load.
+func pickReviewers(candidates sets.String, load map[string]int, count int) []string {
+	reviewers := candidates.List()
+	sort.Stable(byLoad{reviewers, load})
+	if len(reviewers) > count {
+		reviewers = reviewers[:count]
+	}
+	return reviewers
+}
+
+type byLoad struct {
+	logins []string
+	load   map[string]int
+}
+
+func (b byLoad) Len() int           { return len(b.logins) }
+func (b byLoad) Swap(i, j int)      { b.logins[i], b.logins[j] = b.logins[j], b.logins[i] }
+func (b byLoad) Less(i, j int) bool { return b.load[b.logins[i]] < b.load[b.logins[j]] }
+
+// candidates returns the OWNERS reviewers of the files changed by the PR
+func (r *ReviewerAssigner) candidates(obj *github.MungeObject) (sets.String, error) {
+	files, err := obj.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+	candidates := sets.NewString()
+	for _, file := range files {
+		owners := r.features.Repos.LeafReviewers(*file.Filename)
+		if r.features.Aliases != nil && r.features.Aliases.IsEnabled {
+			owners = r.features.Aliases.Expand(owners)
+		}
+		for _, owner := range owners.List() {
+			candidates.Insert(strings.ToLower(owner))
+		}
+	}
+	candidates.Delete(strings.ToLower(*obj.Issue.User.Login))
+	return candidates, nil
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (r *ReviewerAssigner) Munge(obj *github.MungeObject) {
+	if !obj.IsPR() || obj.Issue.User == nil || obj.Issue.User.Login == nil {
+		return
+	}
+
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	assignees := assigneesFromEvents(events)
+	defer func() {
+		for _, login := range assignees.List() {
+			r.nextLoad[login]++
+		}
+	}()
+
+	requests := assignCommands(comments)
+	if len(requests) != 0 {
+		assign, unassign := pendingAssignments(requests, events)
+		if len(assign) != 0 && obj.AddAssignees(assign) == nil {
+			assignees.Insert(assign...)
+		}
+		for _, login := range unassign {
+			if obj.UnassignPR(login) == nil {
+				assignees.Delete(login)
+			}
+		}
+	}
+
+	if !needsReviewers(assignees, events) {
+		return
+	}
+
+	candidates, err := r.candidates(obj)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	// Don't pick the users who asked to be unassigned
+	for login, request := range requests {
+		if !request.assign {
+			candidates.Delete(login)
+		}
+	}
+	reviewers := pickReviewers(candidates, r.load, r.Count)
+	if len(reviewers) == 0 {
+		glog.V(2).Infof("No reviewers found for PR %d", *obj.Issue.Number)
+		return
+	}
+	if err := obj.AddAssignees(reviewers); err != nil {
+		return
+	}
+	for _, login := range reviewers {
+		// Balance the assignments made during this loop too
+		r.load[login]++
+	}
+	assignees.Insert(reviewers...)
+}
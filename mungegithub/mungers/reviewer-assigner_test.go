@@ -0,0 +1,190 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+func makeUnassignEvent(assignee string, at time.Time) *github.IssueEvent {
+	event := makeAssignEvent(assignee, at)
+	unassigned := "unassigned"
+	event.Event = &unassigned
+	return event
+}
+
+func TestAssigneesFromEvents(t *testing.T) {
+	events := []*github.IssueEvent{
+		makeAssignEvent("Alice", time.Unix(10, 0)),
+		makeAssignEvent("bob", time.Unix(20, 0)),
+		makeUnassignEvent("alice", time.Unix(30, 0)),
+		makeAssignEvent("carol", time.Unix(40, 0)),
+	}
+	expected := []string{"bob", "carol"}
+	if assignees := assigneesFromEvents(events).List(); !reflect.DeepEqual(assignees, expected) {
+		t.Errorf("Expected %v, got %v", expected, assignees)
+	}
+}
+
+func TestPendingAssignments(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []*github.IssueComment
+		events   []*github.IssueEvent
+		assign   []string
+		unassign []string
+	}{
+		{
+			name:     "Assign someone",
+			comments: []*github.IssueComment{github_test.Comment(1, "author", time.Unix(10, 0), "/assign @Alice @bob")},
+			assign:   []string{"alice", "bob"},
+			unassign: []string{},
+		},
+		{
+			name:     "Assign myself",
+			comments: []*github.IssueComment{github_test.Comment(1, "carol", time.Unix(10, 0), "/assign")},
+			assign:   []string{"carol"},
+			unassign: []string{},
+		},
+		{
+			name:     "Already done",
+			comments: []*github.IssueComment{github_test.Comment(1, "author", time.Unix(10, 0), "/assign @alice")},
+			events:   []*github.IssueEvent{makeAssignEvent("alice", time.Unix(20, 0))},
+			assign:   []string{},
+			unassign: []string{},
+		},
+		{
+			name:     "Unassign",
+			comments: []*github.IssueComment{github_test.Comment(1, "alice", time.Unix(10, 0), "/unassign")},
+			events:   []*github.IssueEvent{makeAssignEvent("alice", time.Unix(5, 0))},
+			assign:   []string{},
+			unassign: []string{"alice"},
+		},
+		{
+			name: "Last command wins",
+			comments: []*github.IssueComment{
+				github_test.Comment(1, "author", time.Unix(10, 0), "/assign @alice"),
+				github_test.Comment(2, "author", time.Unix(20, 0), "/unassign @alice"),
+			},
+			assign:   []string{},
+			unassign: []string{},
+		},
+		{
+			name:     "Manual change after the command",
+			comments: []*github.IssueComment{github_test.Comment(1, "author", time.Unix(10, 0), "/assign @alice")},
+			events: []*github.IssueEvent{
+				makeAssignEvent("alice", time.Unix(20, 0)),
+				makeUnassignEvent("alice", time.Unix(30, 0)),
+			},
+			assign:   []string{},
+			unassign: []string{},
+		},
+		{
+			name:     "Comma separated users",
+			comments: []*github.IssueComment{github_test.Comment(1, "author", time.Unix(10, 0), "/assign @alice, @bob,carol")},
+			assign:   []string{"alice", "bob", "carol"},
+			unassign: []string{},
+		},
+		{
+			name:     "Bots can't assign",
+			comments: []*github.IssueComment{github_test.Comment(1, "k8s-merge-robot", time.Unix(10, 0), "/assign @alice")},
+			assign:   []string{},
+			unassign: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		assign, unassign := pendingAssignments(assignCommands(test.comments), test.events)
+		if !reflect.DeepEqual(assign, test.assign) {
+			t.Errorf("%s: expected to assign %v, got %v", test.name, test.assign, assign)
+		}
+		if !reflect.DeepEqual(unassign, test.unassign) {
+			t.Errorf("%s: expected to unassign %v, got %v", test.name, test.unassign, unassign)
+		}
+	}
+}
+
+func TestNeedsReviewers(t *testing.T) {
+	botAssign := makeAssignEvent("alice", time.Unix(10, 0))
+	botAssign.Actor = &github.User{Login: stringPtr(botName)}
+
+	tests := []struct {
+		name      string
+		assignees []string
+		events    []*github.IssueEvent
+		expected  bool
+	}{
+		{
+			name:     "New PR",
+			expected: true,
+		},
+		{
+			name:      "Assigned with a command",
+			assignees: []string{"alice"},
+		},
+		{
+			name:     "Only assignee unassigned with a command",
+			events:   []*github.IssueEvent{makeUnassignEvent("author", time.Unix(20, 0))},
+			expected: true,
+		},
+		{
+			name:   "Reviewers were unassigned",
+			events: []*github.IssueEvent{botAssign, makeUnassignEvent("alice", time.Unix(20, 0))},
+		},
+	}
+	for _, test := range tests {
+		if needs := needsReviewers(sets.NewString(test.assignees...), test.events); needs != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, needs)
+		}
+	}
+}
+
+func TestReviewerAssignerCount(t *testing.T) {
+	for _, count := range []int{-1, 0} {
+		r := &ReviewerAssigner{Count: count}
+		if err := r.Initialize(nil, nil); err == nil {
+			t.Errorf("Expected an error for --reviewer-count=%d", count)
+		}
+	}
+}
+
+func TestPickReviewers(t *testing.T) {
+	candidates := sets.NewString("alice", "bob", "carol", "dave")
+	load := map[string]int{"alice": 3, "bob": 1, "carol": 0, "dave": 1}
+
+	tests := []struct {
+		count    int
+		expected []string
+	}{
+		{count: 1, expected: []string{"carol"}},
+		{count: 2, expected: []string{"carol", "bob"}},
+		{count: 3, expected: []string{"carol", "bob", "dave"}},
+		{count: 10, expected: []string{"carol", "bob", "dave", "alice"}},
+	}
+	for _, test := range tests {
+		if reviewers := pickReviewers(candidates, load, test.count); !reflect.DeepEqual(reviewers, test.expected) {
+			t.Errorf("%d reviewers: expected %v, got %v", test.count, test.expected, reviewers)
+		}
+	}
+}
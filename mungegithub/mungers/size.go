@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path"
 	"regexp"
 	"strings"
 
@@ -47,6 +48,7 @@ type SizeMunger struct {
 	GeneratedFilesFile string
 	genFiles           *sets.String
 	genPrefixes        *[]string
+	genPatterns        *[]string
 }
 
 func init() {
@@ -90,8 +92,10 @@ func (s *SizeMunger) getGeneratedFiles(obj *github.MungeObject) {
 	}
 	files := sets.NewString()
 	prefixes := []string{}
+	patterns := []string{}
 	s.genFiles = &files
 	s.genPrefixes = &prefixes
+	s.genPatterns = &patterns
 
 	file := s.GeneratedFilesFile
 	if len(file) == 0 {
@@ -122,6 +126,12 @@ func (s *SizeMunger) getGeneratedFiles(obj *github.MungeObject) {
 			prefixes = append(prefixes, file)
 		} else if eType == "path" {
 			files.Insert(file)
+		} else if eType == "pattern" {
+			if _, err := path.Match(file, ""); err != nil {
+				glog.Errorf("Invalid pattern in generated docs config %s: %q: %v", s.GeneratedFilesFile, file, err)
+				continue
+			}
+			patterns = append(patterns, file)
 		} else if eType == "paths-from-repo" {
 			docs, err := obj.GetFileContents(file, "")
 			if err != nil {
@@ -140,10 +150,35 @@ func (s *SizeMunger) getGeneratedFiles(obj *github.MungeObject) {
 	}
 	s.genFiles = &files
 	s.genPrefixes = &prefixes
+	s.genPatterns = &patterns
 
 	return
 }
 
+// isGenerated returns true if the file is excluded from the size. Patterns
+// without a slash (such as "zz_generated.*") match the base name of the
+// file, other patterns match the whole path.
+func (s *SizeMunger) isGenerated(filename string) bool {
+	if s.genFiles.Has(filename) {
+		return true
+	}
+	for _, p := range *s.genPrefixes {
+		if strings.HasPrefix(filename, p) {
+			return true
+		}
+	}
+	for _, p := range *s.genPatterns {
+		name := filename
+		if !strings.Contains(p, "/") {
+			name = path.Base(filename)
+		}
+		if matched, _ := path.Match(p, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // Munge is the workhorse the will actually make updates to the PR
 func (s *SizeMunger) Munge(obj *github.MungeObject) {
 	if !obj.IsPR() {
@@ -153,8 +188,6 @@ func (s *SizeMunger) Munge(obj *github.MungeObject) {
 	issue := obj.Issue
 
 	s.getGeneratedFiles(obj)
-	genFiles := *s.genFiles
-	genPrefixes := *s.genPrefixes
 
 	files, err := obj.ListFiles()
 	if err != nil {
@@ -164,17 +197,7 @@ func (s *SizeMunger) Munge(obj *github.MungeObject) {
 	adds := 0
 	dels := 0
 	for _, f := range files {
-		skip := false
-		for _, p := range genPrefixes {
-			if strings.HasPrefix(*f.Filename, p) {
-				skip = true
-				break
-			}
-		}
-		if skip {
-			continue
-		}
-		if genFiles.Has(*f.Filename) {
+		if f.Filename == nil || s.isGenerated(*f.Filename) {
 			continue
 		}
 		if f.Additions != nil {
@@ -0,0 +1,67 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+func TestCalculateSize(t *testing.T) {
+	tests := []struct {
+		adds, dels int
+		expected   string
+	}{
+		{adds: 0, dels: 0, expected: sizeXS},
+		{adds: 5, dels: 5, expected: sizeS},
+		{adds: 29, dels: 0, expected: sizeS},
+		{adds: 50, dels: 49, expected: sizeM},
+		{adds: 100, dels: 0, expected: sizeL},
+		{adds: 500, dels: 499, expected: sizeXL},
+		{adds: 0, dels: 1000, expected: sizeXXL},
+	}
+	for _, test := range tests {
+		if size := calculateSize(test.adds, test.dels); size != test.expected {
+			t.Errorf("%d/%d: expected %s, got %s", test.adds, test.dels, test.expected, size)
+		}
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	files := sets.NewString("pkg/api/deep_copy_generated.go")
+	prefixes := []string{"docs/"}
+	patterns := []string{"zz_generated.*", "pkg/*/types.generated.go"}
+	s := &SizeMunger{genFiles: &files, genPrefixes: &prefixes, genPatterns: &patterns}
+
+	tests := []struct {
+		filename string
+		expected bool
+	}{
+		{filename: "pkg/api/deep_copy_generated.go", expected: true},
+		{filename: "docs/user-guide/README.md", expected: true},
+		{filename: "pkg/apis/batch/zz_generated.deepcopy.go", expected: true},
+		{filename: "pkg/api/types.generated.go", expected: true},
+		{filename: "pkg/api/v1/types.generated.go", expected: false},
+		{filename: "pkg/api/types.go", expected: false},
+	}
+	for _, test := range tests {
+		if generated := s.isGenerated(test.filename); generated != test.expected {
+			t.Errorf("%s: expected generated %v, got %v", test.filename, test.expected, generated)
+		}
+	}
+}
@@ -788,6 +788,7 @@ const (
 	unmergeable             = "PR is unable to be automatically merged. Needs rebase."
 	undeterminedMergability = "Unable to determine is PR is mergeable. Will try again later."
 	noMerge                 = "Will not auto merge because " + doNotMergeLabel + " is present"
+	noMergePrefix           = "Will not auto merge because a " + doNotMergeLabel + "/ label is present"
 	ciFailure               = "Github CI tests are not green."
 	e2eFailure              = "The e2e tests are failing. The entire submit queue is blocked."
 	e2eRecover              = "The e2e tests started passing. The submit queue is unblocked."
@@ -908,6 +909,10 @@ func (sq *SubmitQueue) validForMerge(obj *github.MungeObject) bool {
 		sq.SetMergeStatus(obj, noMerge)
 		return false
 	}
+	if len(github.GetLabelsWithPrefix(obj.Issue.Labels, doNotMergeLabel+"/")) != 0 {
+		sq.SetMergeStatus(obj, noMergePrefix)
+		return false
+	}
 
 	return true
 }
@@ -1337,7 +1342,7 @@ func (sq *SubmitQueue) serveMergeInfo(res http.ResponseWriter, req *http.Request
 	out.WriteString(fmt.Sprintf("<li>The PR cannot have any of the following milestones: %q</li>", sq.DoNotMergeMilestones))
 	out.WriteString(fmt.Sprintf(`<li>The PR must have the %q label</li>`, lgtmLabel))
 	out.WriteString(fmt.Sprintf("<li>The PR must not have been updated since the %q label was applied</li>", lgtmLabel))
-	out.WriteString(fmt.Sprintf("<li>The PR must not have the %q label</li>", doNotMergeLabel))
+	out.WriteString(fmt.Sprintf("<li>The PR must not have the %q label, or any label starting with %q</li>", doNotMergeLabel, doNotMergeLabel+"/"))
 	out.WriteString(`</ol><br>`)
 	out.WriteString("The PR can then be queued to re-test before merge. Once it reaches the top of the queue all of the above conditions must be true but so must the following:")
 	out.WriteString("<ol>")
@@ -94,6 +94,10 @@ func DoNotMergeIssue() *github.Issue {
 	return github_test.Issue(someUserName, 1, []string{claYesLabel, lgtmLabel, approvedLabel, doNotMergeLabel}, true)
 }
 
+func BlockedPathsIssue() *github.Issue {
+	return github_test.Issue(someUserName, 1, []string{claYesLabel, lgtmLabel, approvedLabel, blockedPathsLabel}, true)
+}
+
 func DoNotMergeMilestoneIssue() *github.Issue {
 	issue := github_test.Issue(someUserName, 1, []string{claYesLabel, lgtmLabel, doNotMergeLabel}, true)
 	milestone := &github.Milestone{
@@ -761,6 +765,21 @@ func TestSubmitQueue(t *testing.T) {
 			reason:          noMerge,
 			state:           "pending",
 		},
+		{
+			name:            "Fail because a do-not-merge/ label is present",
+			pr:              ValidPR(),
+			issue:           BlockedPathsIssue(),
+			events:          NewLGTMEvents(),
+			commits:         Commits(), // Modified at time.Unix(7), 8, and 9
+			ciStatus:        SuccessStatus(),
+			lastBuildNumber: LastBuildNumber(),
+			gcsResult:       SuccessGCS(),
+			weakResults:     map[int]utils.FinishedFile{LastBuildNumber(): SuccessGCS()},
+			retest1Pass:     true,
+			retest2Pass:     true,
+			reason:          noMergePrefix,
+			state:           "pending",
+		},
 		// Should fail because the 'do-not-merge-milestone' is set.
 		{
 			name:            "Do Not Merge Milestone Set",
@@ -0,0 +1,206 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/contrib/mungegithub/features"
+	mgh "k8s.io/contrib/mungegithub/github"
+	c "k8s.io/contrib/mungegithub/mungers/matchers/comment"
+	e "k8s.io/contrib/mungegithub/mungers/matchers/event"
+
+	"github.com/golang/glog"
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+	"k8s.io/kubernetes/pkg/util/sets"
+)
+
+const (
+	needsTriageLabel      = "needs-triage"
+	triageNotifyName      = "TRIAGE-PING"
+	sigLabelPrefix        = "sig/"
+	triagePriorityPrefix  = "priority/"
+	triageKindLabelPrefix = "kind/"
+	triageSinceLayout     = "2006-01-02"
+)
+
+// TriageMunger adds needs-triage to new issues, and removes it once a
+// member of the triage team sets a priority or kind label. Issues still
+// untriaged after the SLO are escalated by pinging their SIG. Only issues
+// created after --triage-since are labeled, not the existing backlog.
+type TriageMunger struct {
+	TriageTeam       []string
+	SLO              time.Duration
+	SIGMentionFormat string
+	Since            string
+	since            time.Time
+	team             sets.String
+	pinger           *c.Pinger
+}
+
+var _ Munger = &TriageMunger{}
+
+func init() {
+	t := &TriageMunger{}
+	RegisterMungerOrDie(t)
+	RegisterIssueStaleComments(t)
+}
+
+// Name is the name usable in --pr-mungers
+func (t *TriageMunger) Name() string { return "triage" }
+
+// RequiredFeatures is a slice of 'features' that must be provided
+func (t *TriageMunger) RequiredFeatures() []string { return []string{} }
+
+// Initialize will initialize the munger
+func (t *TriageMunger) Initialize(config *mgh.Config, features *features.Features) error {
+	if len(t.TriageTeam) == 0 {
+		return fmt.Errorf("--triage-team is required by the triage munger")
+	}
+	t.since = time.Now()
+	if t.Since != "" {
+		since, err := time.Parse(triageSinceLayout, t.Since)
+		if err != nil {
+			return fmt.Errorf("invalid --triage-since %q, expected YYYY-MM-DD: %v", t.Since, err)
+		}
+		t.since = since
+	}
+	t.team = sets.NewString()
+	for _, login := range t.TriageTeam {
+		t.team.Insert(strings.ToLower(login))
+	}
+	t.pinger = c.NewPinger(triageNotifyName).
+		SetDescription("This issue has not been triaged yet. Please set a priority/ and a kind/ label.").
+		SetTimePeriod(t.SLO)
+	return nil
+}
+
+// EachLoop is called at the start of every munge loop
+func (t *TriageMunger) EachLoop() error { return nil }
+
+// AddFlags will add any request flags to the cobra `cmd`
+func (t *TriageMunger) AddFlags(cmd *cobra.Command, config *mgh.Config) {
+	cmd.Flags().StringSliceVar(&t.TriageTeam, "triage-team", []string{}, "Logins of the people allowed to triage issues")
+	cmd.Flags().DurationVar(&t.SLO, "triage-slo", 7*24*time.Hour, "How long an issue can remain untriaged before its SIG is pinged")
+	cmd.Flags().StringVar(&t.SIGMentionFormat, "triage-sig-mention-format", "@kubernetes/sig-%s-bugs", "Team to ping for a sig/NAME label, NAME replaces %s")
+	cmd.Flags().StringVar(&t.Since, "triage-since", "", "Only triage the issues created after this date (YYYY-MM-DD). Defaults to when the munger starts, leaving the existing issues alone.")
+}
+
+// triagedBy returns a matcher for events triaging the issue, i.e. a member
+// of the team adding a priority or kind label.
+func triagedBy(team sets.String) e.Matcher {
+	actors := []e.Matcher{}
+	for _, login := range team.List() {
+		actors = append(actors, e.Actor(login))
+	}
+	return e.And([]e.Matcher{
+		e.AddLabel{},
+		e.Or([]e.Matcher{e.LabelPrefix(triagePriorityPrefix), e.LabelPrefix(triageKindLabelPrefix)}),
+		e.Or(actors),
+	})
+}
+
+// isTriaged returns true if the team triaged the issue, and the label they
+// set is still there.
+func (t *TriageMunger) isTriaged(issue *github.Issue, events []*github.IssueEvent) bool {
+	labels := sets.NewString(mgh.GetLabelsWithPrefix(issue.Labels, triagePriorityPrefix)...)
+	labels.Insert(mgh.GetLabelsWithPrefix(issue.Labels, triageKindLabelPrefix)...)
+	for _, event := range e.FilterEvents(events, triagedBy(t.team)) {
+		if labels.Has(*event.Label.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNew returns true if the issue was created after the cutoff
+func (t *TriageMunger) isNew(issue *github.Issue) bool {
+	return issue.CreatedAt != nil && issue.CreatedAt.After(t.since)
+}
+
+// owners returns who should be pinged for an untriaged issue: its SIGs,
+// or the triage team when it has none.
+func (t *TriageMunger) owners(issue *github.Issue) string {
+	sigs := mgh.GetLabelsWithPrefix(issue.Labels, sigLabelPrefix)
+	sort.Strings(sigs)
+	mentions := []string{}
+	for _, sig := range sigs {
+		mentions = append(mentions, fmt.Sprintf(t.SIGMentionFormat, strings.TrimPrefix(sig, sigLabelPrefix)))
+	}
+	if len(mentions) == 0 {
+		for _, login := range t.team.List() {
+			mentions = append(mentions, "@"+login)
+		}
+	}
+	return strings.Join(mentions, " ")
+}
+
+// Munge is the workhorse the will actually make updates to the PR
+func (t *TriageMunger) Munge(obj *mgh.MungeObject) {
+	if obj.IsPR() {
+		return
+	}
+
+	events, err := obj.GetEvents()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+
+	if t.isTriaged(obj.Issue, events) {
+		if obj.HasLabel(needsTriageLabel) {
+			obj.RemoveLabel(needsTriageLabel)
+		}
+		return
+	}
+	if !t.isNew(obj.Issue) {
+		return
+	}
+
+	// Don't add the label back if someone removed it.
+	neverLabeled := e.FilterEvents(events, e.And([]e.Matcher{e.AddLabel{}, e.LabelName(needsTriageLabel)})).Empty()
+	if !obj.HasLabel(needsTriageLabel) {
+		if neverLabeled {
+			obj.AddLabel(needsTriageLabel)
+		}
+		return
+	}
+
+	comments, err := obj.ListComments()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	// The first ping is sent one SLO after the issue was created.
+	notif := t.pinger.PingNotification(comments, t.owners(obj.Issue), obj.Issue.CreatedAt)
+	if notif != nil {
+		obj.WriteComment(notif.String())
+	}
+}
+
+// StaleComments returns a slice of stale comments
+func (t *TriageMunger) StaleComments(obj *mgh.MungeObject, comments []*github.IssueComment) []*github.IssueComment {
+	// Pings are obsolete once the issue no longer needs triage
+	if obj.HasLabel(needsTriageLabel) {
+		return nil
+	}
+	return c.FilterComments(comments, c.MungerNotificationName(triageNotifyName))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mungers
+
+import (
+	"testing"
+	"time"
+
+	github_test "k8s.io/contrib/mungegithub/github/testing"
+
+	"github.com/google/go-github/github"
+)
+
+func triageLabelEvent(label, actor string, at time.Time) *github.IssueEvent {
+	event := "labeled"
+	return &github.IssueEvent{
+		Event:     &event,
+		Label:     &github.Label{Name: &label},
+		Actor:     &github.User{Login: &actor},
+		CreatedAt: &at,
+	}
+}
+
+func TestTriageMunger(t *testing.T) {
+	m := &TriageMunger{TriageTeam: []string{"Triager"}, SLO: 7 * day, SIGMentionFormat: "@kubernetes/sig-%s-bugs"}
+	if err := m.Initialize(nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		labels  []string
+		events  []*github.IssueEvent
+		triaged bool
+		owners  string
+	}{
+		{
+			name:   "New issue",
+			labels: []string{needsTriageLabel},
+			owners: "@triager",
+		},
+		{
+			name:    "Priority set by the triage team",
+			labels:  []string{needsTriageLabel, "priority/P1"},
+			events:  []*github.IssueEvent{triageLabelEvent("priority/P1", "triager", time.Unix(10, 0))},
+			triaged: true,
+			owners:  "@triager",
+		},
+		{
+			name:   "Kind set by someone else",
+			labels: []string{needsTriageLabel, "kind/bug", "sig/node"},
+			events: []*github.IssueEvent{triageLabelEvent("kind/bug", "user", time.Unix(10, 0))},
+			owners: "@kubernetes/sig-node-bugs",
+		},
+		{
+			name:   "Triage label was removed since",
+			labels: []string{needsTriageLabel, "sig/node", "sig/storage"},
+			events: []*github.IssueEvent{triageLabelEvent("kind/bug", "triager", time.Unix(10, 0))},
+			owners: "@kubernetes/sig-node-bugs @kubernetes/sig-storage-bugs",
+		},
+	}
+	for _, test := range tests {
+		issue := github_test.Issue("user", 1, test.labels, false)
+		if triaged := m.isTriaged(issue, test.events); triaged != test.triaged {
+			t.Errorf("%s: expected triaged %v, got %v", test.name, test.triaged, triaged)
+		}
+		if owners := m.owners(issue); owners != test.owners {
+			t.Errorf("%s: expected owners %q, got %q", test.name, test.owners, owners)
+		}
+	}
+}
+
+func TestTriageSince(t *testing.T) {
+	m := &TriageMunger{TriageTeam: []string{"triager"}, Since: "2016-06-01"}
+	if err := m.Initialize(nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for created, expected := range map[time.Time]bool{
+		time.Date(2016, time.May, 31, 0, 0, 0, 0, time.UTC): false,
+		time.Date(2016, time.June, 2, 0, 0, 0, 0, time.UTC):  true,
+	} {
+		issue := github_test.Issue("user", 1, nil, false)
+		issue.CreatedAt = &created
+		if isNew := m.isNew(issue); isNew != expected {
+			t.Errorf("%v: expected new %v, got %v", created, expected, isNew)
+		}
+	}
+
+	// Defaults to the start of the munger
+	m = &TriageMunger{TriageTeam: []string{"triager"}}
+	if err := m.Initialize(nil, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	old := github_test.Issue("user", 1, nil, false)
+	yesterday := time.Now().Add(-day)
+	old.CreatedAt = &yesterday
+	if m.isNew(old) {
+		t.Errorf("Expected an existing issue not to be triaged")
+	}
+
+	m = &TriageMunger{TriageTeam: []string{"triager"}, Since: "June 1st"}
+	if err := m.Initialize(nil, nil); err == nil {
+		t.Errorf("Expected an error for an invalid date")
+	}
+}